@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/rah-0/testmark/benchutil"
+)
+
+// writeAggregates prints one row per benchmark and metric with the statistics of its repeated runs.
+// Values are formatted with style. Metrics whose coefficient of variation exceeds noiseCV percent are marked as noisy.
+func writeAggregates(out io.Writer, sets []benchutil.SampleSet, style benchutil.Style, noiseCV float64) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "name\tunit\tn\tmean\tmedian\tmin\tmax\tstddev\t95% CI\tCV")
+
+	for _, set := range sets {
+		name := set.Name
+		for _, unit := range set.Units() {
+			s, _ := set.Summary(unit)
+			human := func(v float64) string { return style.Value(unit, v) }
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\t±%s\t%.2f%%",
+				name, unit, s.N,
+				human(s.Mean), human(s.Median), human(s.Min), human(s.Max),
+				human(s.StdDev), human(s.CI95), s.CV)
+			if s.N > 1 && s.CV > noiseCV {
+				fmt.Fprint(tw, "\tnoisy")
+			}
+			fmt.Fprintln(tw)
+			name = ""
+		}
+	}
+	return tw.Flush()
+}
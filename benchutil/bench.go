@@ -110,3 +110,34 @@ func getMemInfo(fn func()) memInfo {
 		bytes:  int64(memAfter.TotalAlloc - memBefore.TotalAlloc),
 	}
 }
+
+// BenchResults holds the results of repeated Measure calls, so that two sets of
+// samples can be compared with a HypothesisTest such as MannWhitneyU or WelchTTest.
+type BenchResults []BenchResult
+
+// MeasureN calls Measure count times and returns every result as a separate sample.
+func (t *Bench) MeasureN(fn func(), count int) BenchResults {
+	out := make(BenchResults, 0, count)
+	for i := 0; i < count; i++ {
+		out = append(out, t.Measure(fn))
+	}
+	return out
+}
+
+// NsPerOp returns the NsPerOp value of every sample.
+func (r BenchResults) NsPerOp() []float64 {
+	out := make([]float64, len(r))
+	for i, res := range r {
+		out[i] = float64(res.NsPerOp)
+	}
+	return out
+}
+
+// BytesPerOp returns the BytesPerOp value of every sample.
+func (r BenchResults) BytesPerOp() []float64 {
+	out := make([]float64, len(r))
+	for i, res := range r {
+		out[i] = float64(res.BytesPerOp)
+	}
+	return out
+}
@@ -105,3 +105,20 @@ func BenchmarkAllocLarge(b *testing.B) {
 		allocLarge()
 	}
 }
+
+func TestMeasureN_Significance(t *testing.T) {
+	tuner := NewBench().SetRuns(100)
+	small := tuner.MeasureN(func() {
+		_ = allocSmall()
+	}, 5)
+	large := tuner.MeasureN(func() {
+		_ = allocLarge()
+	}, 5)
+
+	if len(small) != 5 || len(large.BytesPerOp()) != 5 {
+		t.Fatalf("Expected 5 samples per set, got %d and %d", len(small), len(large.BytesPerOp()))
+	}
+	if p := MannWhitneyU(small.BytesPerOp(), large.BytesPerOp()); p > 0.05 {
+		t.Errorf("Expected a significant B/op difference, got p=%.3f", p)
+	}
+}
@@ -4,8 +4,6 @@
 package benchutil
 
 import (
-	"fmt"
-	"slices"
 	"strconv"
 	"strings"
 
@@ -13,131 +11,86 @@ import (
 )
 
 // AppendConvertedLine takes a standard Go benchmark output line and appends human-friendly conversions.
-// It detects ns/op, B/op, and allocs/op measurements, and appends more readable
-// representations like "CPU[3ms 651µs 349ns]" and "MEM[1KiB 104B]".
+// Every "value unit" pair is kept, and readable representations like "CPU[3ms 651µs 349ns]"
+// and "MEM[1KiB 104B]" are appended for ns/op and B/op. Custom metrics reported with
+// b.ReportMetric whose unit starts with a time or byte unit get their own annotation,
+// such as "ns/key[1µs 200ns]".
 // If the input line is not a benchmark line, it returns the original line unmodified.
 func AppendConvertedLine(line string) string {
-	hasNsOp := strings.Contains(line, "ns/op")
-	hasBOp := strings.Contains(line, "B/op")
-	hasAllocsOp := strings.Contains(line, "allocs/op")
-	if !hasNsOp && !hasBOp && !hasAllocsOp {
+	bl, ok := ParseLine(line)
+	if !ok {
 		return line
 	}
+	return FormatLine(bl)
+}
 
-	fields := strings.Fields(line)
-
-	label := fields[0]
-	count := fields[1]
-
-	nsOp := ""
-	if hasNsOp {
-		nsOp = fields[slices.Index(fields, "ns/op")-1]
-	}
-
-	bOp := ""
-	if hasBOp {
-		bOp = fields[slices.Index(fields, "B/op")-1]
-	}
-
-	allocsOp := ""
-	if hasAllocsOp {
-		allocsOp = fields[slices.Index(fields, "allocs/op")-1]
-	}
-
-	parts := []string{label, count}
-	if hasNsOp {
-		parts = append(parts, nsOp+" ns/op")
-	}
-	if hasBOp {
-		parts = append(parts, bOp+" B/op")
-	}
-	if hasAllocsOp {
-		parts = append(parts, allocsOp+" allocs/op")
-	}
-
-	if hasNsOp {
-		nsHuman := HumanNs(int64(parseFloat(nsOp)))
-		if nsHuman != nsOp+"ns" && nsHuman != "0ns" {
-			parts = append(parts, "CPU["+nsHuman+"]")
-		}
-	}
-	if hasBOp {
-		bHuman := HumanBytes(int64(parseFloat(bOp)))
-		if bHuman != bOp+"B" {
-			parts = append(parts, "MEM["+bHuman+"]")
-		}
-	}
-
-	return strings.Join(parts, "\t")
+// FormatLine renders a parsed benchmark line in testmark's text format using the default Formatter.
+func FormatLine(bl BenchLine) string {
+	return NewFormatter().FormatLine(bl)
 }
 
 // HumanNs converts nanoseconds to a human-readable string with appropriate units.
-// It breaks down the time into hours, minutes, seconds, milliseconds, microseconds, and nanoseconds
-// as appropriate for the magnitude of the input.
+// It breaks down the time into days, hours, minutes, seconds, milliseconds, microseconds, and nanoseconds
+// as appropriate for the magnitude of the input. Negative durations are prefixed with "-".
 func HumanNs(ns int64) string {
-	out := ""
-
-	if ns >= model.Hour {
-		out += fmt.Sprintf("%dh ", ns/model.Hour)
-		ns %= model.Hour
-	}
-	if ns >= model.Min {
-		out += fmt.Sprintf("%dm ", ns/model.Min)
-		ns %= model.Min
-	}
-	if ns >= model.Sec {
-		out += fmt.Sprintf("%ds ", ns/model.Sec)
-		ns %= model.Sec
-	}
-	if ns >= model.Milli {
-		out += fmt.Sprintf("%dms ", ns/model.Milli)
-		ns %= model.Milli
-	}
-	if ns >= model.Micro {
-		out += fmt.Sprintf("%dµs ", ns/model.Micro)
-		ns %= model.Micro
-	}
-	if ns > 0 || out == "" {
-		out += fmt.Sprintf("%dns", ns)
-	}
+	return Style{}.Ns(ns)
+}
 
-	return trimTrailingSpace(out)
+// HumanNsFloat converts a fractional number of nanoseconds, such as a sub-nanosecond ns/op, to a human-readable
+// string with picosecond precision, e.g. "251ps" for 0.2513 or "12ns 750ps" for 12.75.
+// A positive value is never shown as "0ns".
+func HumanNsFloat(ns float64) string {
+	return Style{}.NsFloat(ns)
 }
 
 // HumanBytes converts a byte count to a human-readable string with appropriate units.
-// It scales the value using binary prefixes (KiB, MiB, GiB) based on powers of 1024.
+// It scales the value using binary prefixes (KiB, MiB, GiB, TiB, PiB, EiB) based on powers of 1024.
+// Negative sizes are prefixed with "-".
 func HumanBytes(b int64) string {
-	out := ""
-	if b >= model.GiB {
-		out += fmt.Sprintf("%dGiB ", b/model.GiB)
-		b %= model.GiB
-	}
-	if b >= model.MiB {
-		out += fmt.Sprintf("%dMiB ", b/model.MiB)
-		b %= model.MiB
-	}
-	if b >= model.KiB {
-		out += fmt.Sprintf("%dKiB ", b/model.KiB)
-		b %= model.KiB
-	}
-	if b > 0 || out == "" {
-		out += fmt.Sprintf("%dB", b)
-	}
-	return trimTrailingSpace(out)
+	return Style{}.Bytes(b)
 }
 
-// parseFloat parses float-like numbers from Go benchmark lines.
-func parseFloat(s string) float64 {
-	f, _ := strconv.ParseFloat(s, 64)
-	return f
+// ParseHumanNs parses a human-readable duration, such as "3ms 651µs" from HumanNs, back to nanoseconds.
+// It accepts the terms of model.ParseDuration, so HumanNs output round-trips exactly.
+func ParseHumanNs(s string) (int64, error) {
+	d, err := model.ParseDuration(s)
+	return int64(d), err
+}
+
+// ParseHumanBytes parses a human-readable size, such as "1KiB 104B" from HumanBytes, back to bytes.
+// It accepts the terms of model.ParseSize, including decimal kB/MB/GB, so HumanBytes output round-trips exactly.
+func ParseHumanBytes(s string) (int64, error) {
+	b, err := model.ParseSize(s)
+	return int64(b), err
+}
+
+// HumanThroughput converts a rate in bytes per second to a human-readable string.
+// It uses decimal prefixes (KB/s, MB/s, GB/s, TB/s, PB/s, EB/s) to match the MB/s column printed by the testing package.
+func HumanThroughput(bytesPerSec float64) string {
+	return humanDecimal(bytesPerSec, "B/s")
+}
+
+// HumanOpsPerSec converts an operation rate to a human-readable string such as "8.73K/s".
+func HumanOpsPerSec(opsPerSec float64) string {
+	return humanDecimal(opsPerSec, "/s")
+}
+
+// humanDecimal scales v by powers of 1000 and formats it with up to two decimals followed by the prefix and suffix.
+func humanDecimal(v float64, suffix string) string {
+	prefixes := []string{"", "K", "M", "G", "T", "P", "E"}
+	i := 0
+	for (v >= 1000 || v <= -1000) && i < len(prefixes)-1 {
+		v /= 1000
+		i++
+	}
+	return formatNumber(v) + prefixes[i] + suffix
 }
 
-// trimTrailingSpace removes a single trailing space from a string if one exists.
-// This is used to clean up the formatted output strings.
-func trimTrailingSpace(s string) string {
-	n := len(s)
-	if n > 0 && s[n-1] == ' ' {
-		return s[:n-1]
+// formatNumber formats v with up to two decimals, trimming trailing zeros.
+func formatNumber(v float64) string {
+	num := strconv.FormatFloat(v, 'f', 2, 64)
+	if strings.Contains(num, ".") {
+		num = strings.TrimRight(strings.TrimRight(num, "0"), ".")
 	}
-	return s
+	return num
 }
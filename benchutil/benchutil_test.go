@@ -1,7 +1,10 @@
 package benchutil
 
 import (
+	"math"
 	"testing"
+
+	"github.com/rah-0/testmark/model"
 )
 
 func TestAppendConvertedLine(t *testing.T) {
@@ -13,7 +16,7 @@ func TestAppendConvertedLine(t *testing.T) {
 		// ns/op only
 		{
 			"BenchmarkFastOp-8          1000000    31.2 ns/op",
-			"BenchmarkFastOp-8\t1000000\t31.2 ns/op\tCPU[31ns]",
+			"BenchmarkFastOp-8\t1000000\t31.2 ns/op\tCPU[31ns 200ps]",
 		},
 
 		// ns/op + B/op
@@ -37,7 +40,7 @@ func TestAppendConvertedLine(t *testing.T) {
 		// sub-nanosecond op
 		{
 			"BenchmarkNanoOp-8          10000000    0.9 ns/op",
-			"BenchmarkNanoOp-8\t10000000\t0.9 ns/op",
+			"BenchmarkNanoOp-8\t10000000\t0.9 ns/op\tCPU[900ps]",
 		},
 
 		// no spacing at all (single spaces)
@@ -61,7 +64,7 @@ func TestAppendConvertedLine(t *testing.T) {
 		// values with decimal precision
 		{
 			"BenchmarkDecimal-8    1000   1234.56 ns/op   789.12 B/op   3 allocs/op",
-			"BenchmarkDecimal-8\t1000\t1234.56 ns/op\t789.12 B/op\t3 allocs/op\tCPU[1µs 234ns]\tMEM[789B]",
+			"BenchmarkDecimal-8\t1000\t1234.56 ns/op\t789.12 B/op\t3 allocs/op\tCPU[1µs 234ns 560ps]\tMEM[789B]",
 		},
 
 		// unusual label with special characters
@@ -94,6 +97,24 @@ func TestAppendConvertedLine(t *testing.T) {
 			"BenchmarkWeirdSpace-8\t10\t5000 ns/op\t1024 B/op\t4 allocs/op\tCPU[5µs]\tMEM[1KiB]",
 		},
 
+		// custom metrics from b.ReportMetric are kept
+		{
+			"BenchmarkCustom-8    1000    1500 ns/op    2500 ns/key    1200000 p99-ns    3 items/s    64 B/op    1 allocs/op",
+			"BenchmarkCustom-8\t1000\t1500 ns/op\t2500 ns/key\t1200000 p99-ns\t3 items/s\t64 B/op\t1 allocs/op\tCPU[1µs 500ns]\tns/key[2µs 500ns]\tp99-ns[1ms 200µs]",
+		},
+
+		// custom byte metrics
+		{
+			"BenchmarkBytesMsg-8    1000    100 ns/op    2048 bytes/msg    2.5 µs/call",
+			"BenchmarkBytesMsg-8\t1000\t100 ns/op\t2048 bytes/msg\t2.5 µs/call\tbytes/msg[2KiB]\tµs/call[2µs 500ns]",
+		},
+
+		// only custom metrics
+		{
+			"BenchmarkOnlyCustom-8    1000    17 items/s",
+			"BenchmarkOnlyCustom-8\t1000\t17 items/s",
+		},
+
 		// malformed line
 		{
 			"Some unrelated log output",
@@ -132,6 +153,91 @@ func TestHumanNs(t *testing.T) {
 	}
 }
 
+func TestHumanNsExtendedRange(t *testing.T) {
+	tests := []struct {
+		input int64
+		want  string
+	}{
+		{2*model.Day + 3*model.Hour, "2d 3h"},
+		{model.Day, "1d"},
+		{-1500, "-1µs 500ns"},
+		{-5, "-5ns"},
+		{math.MaxInt64, "106751d 23h 47m 16s 854ms 775µs 807ns"},
+		{math.MinInt64, "-106751d 23h 47m 16s 854ms 775µs 808ns"},
+	}
+	for _, tt := range tests {
+		if got := HumanNs(tt.input); got != tt.want {
+			t.Errorf("HumanNs(%d) = %q, want %q", tt.input, got, tt.want)
+		}
+		if got, err := ParseHumanNs(tt.want); err != nil || got != tt.input {
+			t.Errorf("ParseHumanNs(%q) = %d, %v, want %d", tt.want, got, err, tt.input)
+		}
+	}
+}
+
+func TestHumanBytesExtendedRange(t *testing.T) {
+	tests := []struct {
+		input int64
+		want  string
+	}{
+		{5 * model.TiB, "5TiB"},
+		{model.PiB + model.GiB, "1PiB 1GiB"},
+		{2 * model.EiB, "2EiB"},
+		{-1128, "-1KiB 104B"},
+		{math.MaxInt64, "7EiB 1023PiB 1023TiB 1023GiB 1023MiB 1023KiB 1023B"},
+		{math.MinInt64, "-8EiB"},
+	}
+	for _, tt := range tests {
+		if got := HumanBytes(tt.input); got != tt.want {
+			t.Errorf("HumanBytes(%d) = %q, want %q", tt.input, got, tt.want)
+		}
+		if got, err := ParseHumanBytes(tt.want); err != nil || got != tt.input {
+			t.Errorf("ParseHumanBytes(%q) = %d, %v, want %d", tt.want, got, err, tt.input)
+		}
+		if got := model.Size(tt.input).String(); got != tt.want {
+			t.Errorf("model.Size(%d).String() = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+
+	if got := (Style{SI: true}).Bytes(5 * model.TB); got != "5TB" {
+		t.Errorf("SI Bytes(5TB) = %q, want 5TB", got)
+	}
+	if got := (Style{Compact: true}).Bytes(5*model.TiB + model.TiB/2); got != "5.5TiB" {
+		t.Errorf("compact Bytes(5.5TiB) = %q, want 5.5TiB", got)
+	}
+	if got := HumanValue("B/op", 1e30); got != HumanBytes(math.MaxInt64) {
+		t.Errorf("HumanValue(B/op, 1e30) = %q, want the clamped %q", got, HumanBytes(math.MaxInt64))
+	}
+}
+
+func TestHumanNsFloat(t *testing.T) {
+	tests := []struct {
+		input float64
+		want  string
+	}{
+		{0, "0ns"},
+		{0.2513, "251ps"},
+		{0.0004, "<1ps"},
+		{0.9, "900ps"},
+		{1, "1ns"},
+		{12.75, "12ns 750ps"},
+		{1234.56, "1µs 234ns 560ps"},
+		{3651349, "3ms 651µs 349ns"},
+		{1e17, "1157d 9h 46m 40s"},
+		{-12.75, "-12ns 750ps"},
+		{-0.0001, "-<1ps"},
+		{1e300, "106751d 23h 47m 16s 854ms 775µs 807ns"},
+		{math.NaN(), "NaN"},
+	}
+
+	for _, tt := range tests {
+		got := HumanNsFloat(tt.input)
+		if got != tt.want {
+			t.Errorf("HumanNsFloat(%v) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
 func TestHumanBytes(t *testing.T) {
 	tests := []struct {
 		input int64
@@ -163,3 +269,75 @@ func TestHumanBytes(t *testing.T) {
 		}
 	}
 }
+
+func TestHumanThroughput(t *testing.T) {
+	tests := []struct {
+		input float64
+		want  string
+	}{
+		{0, "0B/s"},
+		{512, "512B/s"},
+		{1500, "1.5KB/s"},
+		{12500000, "12.5MB/s"},
+		{1250750000, "1.25GB/s"},
+		{3e12, "3TB/s"},
+	}
+
+	for _, tt := range tests {
+		got := HumanThroughput(tt.input)
+		if got != tt.want {
+			t.Errorf("HumanThroughput(%v) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestHumanOpsPerSec(t *testing.T) {
+	tests := []struct {
+		input float64
+		want  string
+	}{
+		{0.5, "0.5/s"},
+		{999, "999/s"},
+		{8739.05, "8.74K/s"},
+		{1e6, "1M/s"},
+	}
+
+	for _, tt := range tests {
+		got := HumanOpsPerSec(tt.input)
+		if got != tt.want {
+			t.Errorf("HumanOpsPerSec(%v) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseHumanRoundTrip(t *testing.T) {
+	for _, ns := range []int64{0, 1, 999, 1000, 1500, 1234567, 3661000000000, 3651349} {
+		s := HumanNs(ns)
+		got, err := ParseHumanNs(s)
+		if err != nil || got != ns {
+			t.Errorf("ParseHumanNs(%q) = %d, %v, want %d", s, got, err, ns)
+		}
+		if d := model.Duration(ns).String(); d != s {
+			t.Errorf("model.Duration(%d).String() = %q, want %q like HumanNs", ns, d, s)
+		}
+	}
+	for _, b := range []int64{0, 512, 1024, 1128, 1048576 + 1, 3<<30 + 5} {
+		for _, style := range []Style{{}, {SI: true}} {
+			s := style.Bytes(b)
+			got, err := ParseHumanBytes(s)
+			if err != nil || got != b {
+				t.Errorf("ParseHumanBytes(%q) = %d, %v, want %d", s, got, err, b)
+			}
+		}
+		if s := model.Size(b).String(); s != HumanBytes(b) {
+			t.Errorf("model.Size(%d).String() = %q, want %q like HumanBytes", b, s, HumanBytes(b))
+		}
+	}
+
+	if got, err := ParseHumanNs("3.65ms"); err != nil || got != 3650000 {
+		t.Errorf("ParseHumanNs(3.65ms) = %d, %v, want 3650000", got, err)
+	}
+	if _, err := ParseHumanBytes("3ms"); err == nil {
+		t.Error("ParseHumanBytes(3ms) succeeded, want error")
+	}
+}
@@ -0,0 +1,181 @@
+package benchutil
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// blockWriter writes records in text format, buffering consecutive benchmark lines as one block
+// so they can be aligned and charted together.
+type blockWriter struct {
+	w io.Writer
+	f *Formatter
+	// aligned lays the block out in columns with elastic tab stops
+	aligned bool
+	// chartUnit is the metric drawn as a bar, empty for no bars
+	chartUnit string
+	// chartWidth is the width in cells of the longest bar
+	chartWidth int
+	block      []Record
+}
+
+// NewAlignedTextWriter returns a RecordWriter that prints records like NewTextWriter, but lays out every block
+// of consecutive benchmark lines in aligned columns: every metric unit and every annotation label of the block
+// gets its own column, left empty on lines that lack it.
+// A block ends at the next log record, such as a "pkg:" header, or when the package or the GroupKey of the benchmarks changes.
+// Blocks of grouped benchmarks are preceded by a "group: <key>" line like in NewTextWriter.
+// Blocks are written when they end and on Flush.
+func NewAlignedTextWriter(w io.Writer, f *Formatter) RecordWriter {
+	return &blockWriter{w: w, f: f, aligned: true}
+}
+
+// NewChartWriter returns a RecordWriter that prints records like NewTextWriter and appends a bar
+// to every benchmark line, drawn from the metric with the given unit and scaled to the largest value
+// of the benchmark's Group within the block, so the dominating sub-benchmark stands out.
+// The longest bar is width cells. If aligned is true, blocks are laid out like NewAlignedTextWriter does.
+// Blocks of consecutive benchmark lines are written when they end and on Flush.
+func NewChartWriter(w io.Writer, f *Formatter, unit string, width int, aligned bool) RecordWriter {
+	return &blockWriter{w: w, f: f, aligned: aligned, chartUnit: unit, chartWidth: width}
+}
+
+func (b *blockWriter) Write(r Record) error {
+	if r.Type == RecordBenchmark {
+		if len(b.block) > 0 && (recordPkg(b.block[0]) != recordPkg(r) || b.block[0].GroupKey != r.GroupKey) {
+			if err := b.Flush(); err != nil {
+				return err
+			}
+		}
+		b.block = append(b.block, r)
+		return nil
+	}
+	if err := b.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(b.w, r.Text)
+	return err
+}
+
+func (b *blockWriter) Flush() error {
+	if len(b.block) == 0 {
+		return nil
+	}
+	bars := b.bars()
+	rows := b.rows()
+
+	if key := b.block[0].GroupKey; key != "" {
+		if _, err := fmt.Fprintln(b.w, groupHeader(key)); err != nil {
+			return err
+		}
+	}
+	out := b.w
+	var tw *tabwriter.Writer
+	if b.aligned {
+		tw = tabwriter.NewWriter(b.w, 0, 0, 2, ' ', 0)
+		out = tw
+	}
+	for i, row := range rows {
+		if bars[i] != "" {
+			row = append(row, bars[i])
+		} else {
+			for len(row) > 0 && row[len(row)-1] == "" {
+				row = row[:len(row)-1]
+			}
+		}
+		if _, err := fmt.Fprintln(out, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	b.block = nil
+	if tw != nil {
+		return tw.Flush()
+	}
+	return nil
+}
+
+// rows returns the cells of every benchmark line of the block. Unaligned rows hold the fields of
+// Formatter.FormatLine. Aligned rows hold a cell for every metric unit and annotation label of the block,
+// in order of first appearance, so a bar appended to the row lines up with the others.
+func (b *blockWriter) rows() [][]string {
+	out := make([][]string, len(b.block))
+	if !b.aligned {
+		for i, r := range b.block {
+			out[i] = strings.Split(b.f.FormatLine(*r.BenchLine), "\t")
+		}
+		return out
+	}
+
+	var units, labels []string
+	seenUnit, seenLabel := map[string]bool{}, map[string]bool{}
+	annotations := make([][]Annotation, len(b.block))
+	for i, r := range b.block {
+		for _, m := range r.Metrics {
+			if !seenUnit[m.Unit] {
+				seenUnit[m.Unit] = true
+				units = append(units, m.Unit)
+			}
+		}
+		annotations[i] = b.f.Annotate(*r.BenchLine)
+		for _, a := range annotations[i] {
+			if !seenLabel[a.Label] {
+				seenLabel[a.Label] = true
+				labels = append(labels, a.Label)
+			}
+		}
+	}
+
+	for i, r := range b.block {
+		row := []string{r.Name, strconv.FormatInt(r.Iterations, 10)}
+		for _, u := range units {
+			cell := ""
+			if m, ok := r.Metric(u); ok {
+				cell = m.Raw + " " + m.Unit
+			}
+			row = append(row, cell)
+		}
+		for _, l := range labels {
+			cell := ""
+			for _, a := range annotations[i] {
+				if a.Label == l {
+					cell = a.String()
+					break
+				}
+			}
+			row = append(row, cell)
+		}
+		out[i] = row
+	}
+	return out
+}
+
+// bars returns the bar of every benchmark line of the block, empty if charts are disabled
+// or the line lacks the charted metric.
+func (b *blockWriter) bars() []string {
+	out := make([]string, len(b.block))
+	if b.chartUnit == "" {
+		return out
+	}
+
+	peak := map[string]float64{}
+	for _, r := range b.block {
+		if m, ok := r.Metric(b.chartUnit); ok && m.Value > peak[r.Group()] {
+			peak[r.Group()] = m.Value
+		}
+	}
+	for i, r := range b.block {
+		if m, ok := r.Metric(b.chartUnit); ok && peak[r.Group()] > 0 {
+			out[i] = Bar(m.Value/peak[r.Group()], b.chartWidth)
+		}
+	}
+	return out
+}
+
+// recordPkg returns the package of a record's environment header, or an empty string if it has none.
+func recordPkg(r Record) string {
+	if r.Env == nil {
+		return ""
+	}
+	return r.Env.Pkg
+}
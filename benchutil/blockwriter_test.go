@@ -0,0 +1,85 @@
+package benchutil
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAlignedTextWriter(t *testing.T) {
+	f := NewFormatter()
+	var buf bytes.Buffer
+	w := NewAlignedTextWriter(&buf, f)
+	for _, l := range []string{
+		"pkg: example",
+		"BenchmarkSample/Count10-8   100000   114430 ns/op   808 B/op   18 allocs/op",
+		"BenchmarkSample/Count1000000-8   100000   37593152 ns/op   1176 B/op   18 allocs/op",
+		"BenchmarkIO-8   100   2000 ns/op   512.00 MB/s",
+		"PASS",
+		"BenchmarkShort-8   10   5 ns/op",
+	} {
+		if err := w.Write(f.Record(l)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `pkg: example
+BenchmarkSample/Count10-8       100000  114430 ns/op    808 B/op   18 allocs/op               CPU[114µs 430ns]
+BenchmarkSample/Count1000000-8  100000  37593152 ns/op  1176 B/op  18 allocs/op               CPU[37ms 593µs 152ns]  MEM[1KiB 152B]
+BenchmarkIO-8                   100     2000 ns/op                               512.00 MB/s  CPU[2µs]
+PASS
+BenchmarkShort-8  10  5 ns/op
+`
+	if buf.String() != want {
+		t.Errorf("aligned output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestAlignedTextWriterPackageChange(t *testing.T) {
+	f := NewFormatter()
+	a, b := f.NewStream(), f.NewStream()
+	a.Record("pkg: a")
+	b.Record("pkg: b")
+
+	var buf bytes.Buffer
+	w := NewAlignedTextWriter(&buf, f)
+	for _, r := range []Record{
+		a.Record("BenchmarkLongName-8   10   5 ns/op"),
+		b.Record("BenchmarkX-8   10   5 ns/op"),
+	} {
+		if err := w.Write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "BenchmarkLongName-8  10  5 ns/op\nBenchmarkX-8  10  5 ns/op\n"
+	if buf.String() != want {
+		t.Errorf("output across packages:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestAlignedChartWriter(t *testing.T) {
+	f := NewFormatter()
+	var buf bytes.Buffer
+	w := NewChartWriter(&buf, f, "ns/op", 4, true)
+	for _, l := range []string{
+		"BenchmarkSample/Count10-8   100   250 ns/op",
+		"BenchmarkSample/Count100-8   100   1000 ns/op",
+	} {
+		_ = w.Write(f.Record(l))
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "BenchmarkSample/Count10-8   100  250 ns/op             █\n" +
+		"BenchmarkSample/Count100-8  100  1000 ns/op  CPU[1µs]  ████\n"
+	if buf.String() != want {
+		t.Errorf("aligned chart output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
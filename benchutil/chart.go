@@ -0,0 +1,59 @@
+package benchutil
+
+import (
+	"math"
+	"strings"
+)
+
+// barBlocks are the partial block characters used by Bar, from 1/8 to 8/8 of a cell.
+var barBlocks = []rune("▏▎▍▌▋▊▉█")
+
+// sparkBlocks are the block characters used by Sparkline, from lowest to highest.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// Bar renders a horizontal bar of fraction times width cells using Unicode block characters,
+// with a resolution of 1/8 of a cell. fraction is clamped to [0, 1].
+// A non-zero fraction always draws at least 1/8 of a cell.
+func Bar(fraction float64, width int) string {
+	if math.IsNaN(fraction) || fraction <= 0 || width <= 0 {
+		return ""
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	eighths := int(math.Round(fraction * float64(width) * 8))
+	if eighths == 0 {
+		eighths = 1
+	}
+	out := strings.Repeat(string(barBlocks[7]), eighths/8)
+	if eighths%8 > 0 {
+		out += string(barBlocks[eighths%8-1])
+	}
+	return out
+}
+
+// Sparkline renders the values as a line of block characters, scaled between their minimum and maximum.
+// NaN values are drawn as spaces. If all values are equal, every block has the same middle height.
+func Sparkline(values []float64) string {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		if !math.IsNaN(v) {
+			lo = math.Min(lo, v)
+			hi = math.Max(hi, v)
+		}
+	}
+
+	var sb strings.Builder
+	for _, v := range values {
+		switch {
+		case math.IsNaN(v):
+			sb.WriteRune(' ')
+		case hi == lo:
+			sb.WriteRune(sparkBlocks[len(sparkBlocks)/2-1])
+		default:
+			i := int(math.Round((v - lo) / (hi - lo) * float64(len(sparkBlocks)-1)))
+			sb.WriteRune(sparkBlocks[i])
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,76 @@
+package benchutil
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestBar(t *testing.T) {
+	tests := []struct {
+		fraction float64
+		width    int
+		want     string
+	}{
+		{1, 4, "████"},
+		{0.5, 4, "██"},
+		{0.5625, 2, "█▏"},
+		{0.01, 4, "▏"},
+		{0, 4, ""},
+		{2, 2, "██"},
+		{math.NaN(), 4, ""},
+	}
+	for _, tt := range tests {
+		if got := Bar(tt.fraction, tt.width); got != tt.want {
+			t.Errorf("Bar(%v, %d) = %q, want %q", tt.fraction, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values []float64
+		want   string
+	}{
+		{[]float64{1, 8, 4.5}, "▁█▅"},
+		{[]float64{100, math.NaN(), 200}, "▁ █"},
+		{[]float64{3, 3}, "▄▄"},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := Sparkline(tt.values); got != tt.want {
+			t.Errorf("Sparkline(%v) = %q, want %q", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestChartWriter(t *testing.T) {
+	f := NewFormatter()
+	var buf bytes.Buffer
+	w := NewChartWriter(&buf, f, "ns/op", 4, false)
+	for _, l := range []string{
+		"goos: linux",
+		"BenchmarkSample/Count10-8   100   250 ns/op",
+		"BenchmarkSample/Count100-8   100   1000 ns/op",
+		"BenchmarkOther-8   100   5 ns/op",
+		"PASS",
+		"BenchmarkLast-8   100   5 ns/op",
+	} {
+		if err := w.Write(f.Record(l)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "goos: linux\n" +
+		"BenchmarkSample/Count10-8\t100\t250 ns/op\t█\n" +
+		"BenchmarkSample/Count100-8\t100\t1000 ns/op\tCPU[1µs]\t████\n" +
+		"BenchmarkOther-8\t100\t5 ns/op\t████\n" +
+		"PASS\n" +
+		"BenchmarkLast-8\t100\t5 ns/op\t████\n"
+	if buf.String() != want {
+		t.Errorf("chart output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,239 @@
+package benchutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Percent is a percentage threshold such as 5 for "5%".
+// It can be parsed from "5%", "5" or a JSON number or string.
+type Percent float64
+
+// ParsePercent parses a percentage with an optional "%" suffix, e.g. "5%", "0.5" or "0".
+func ParsePercent(s string) (Percent, error) {
+	v, err := strconv.ParseFloat(strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "%")), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid percentage %q", s)
+	}
+	return Percent(v), nil
+}
+
+// String returns the percentage with a "%" suffix.
+func (p Percent) String() string {
+	return formatNumber(float64(p)) + "%"
+}
+
+// UnmarshalJSON accepts a JSON number or a string accepted by ParsePercent.
+func (p *Percent) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var f float64
+		if err := json.Unmarshal(data, &f); err != nil {
+			return fmt.Errorf("invalid percentage %s", data)
+		}
+		*p = Percent(f)
+		return nil
+	}
+	v, err := ParsePercent(s)
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
+
+// Thresholds are the maximum allowed regressions of a benchmark, in percent over the baseline.
+// A nil threshold is not checked.
+type Thresholds struct {
+	// Time is the maximum allowed increase of ns/op
+	Time *Percent `json:"max-time-regress,omitempty"`
+	// Mem is the maximum allowed increase of B/op
+	Mem *Percent `json:"max-mem-regress,omitempty"`
+	// Allocs is the maximum allowed increase of allocs/op
+	Allocs *Percent `json:"max-alloc-regress,omitempty"`
+}
+
+// Merge returns t with every threshold that is set in o replaced by the one from o.
+func (t Thresholds) Merge(o Thresholds) Thresholds {
+	if o.Time != nil {
+		t.Time = o.Time
+	}
+	if o.Mem != nil {
+		t.Mem = o.Mem
+	}
+	if o.Allocs != nil {
+		t.Allocs = o.Allocs
+	}
+	return t
+}
+
+// CheckRule overrides thresholds for the benchmarks whose full name matches Pattern.
+type CheckRule struct {
+	// Pattern is a regular expression matched against the full benchmark name, like go test -bench
+	Pattern string `json:"pattern"`
+	Thresholds
+
+	re *regexp.Regexp
+}
+
+// CheckConfig configures a regression check. The global thresholds apply to every benchmark,
+// and every rule whose pattern matches a benchmark overrides them, with later rules winning.
+//
+// In JSON form:
+//
+//	{
+//	  "max-time-regress": "5%",
+//	  "max-alloc-regress": 0,
+//	  "rules": [
+//	    {"pattern": "^BenchmarkSample/", "max-time-regress": "10%"}
+//	  ]
+//	}
+type CheckConfig struct {
+	Thresholds
+	// Rules are the per-benchmark overrides, applied in order
+	Rules []CheckRule `json:"rules,omitempty"`
+	// Alpha is the significance level used when both sides have repeated runs:
+	// regressions with a higher p-value are treated as noise. nil or 0 disables the significance check.
+	Alpha *float64 `json:"alpha,omitempty"`
+}
+
+// ReadCheckConfig decodes a JSON check configuration and compiles its rule patterns.
+func ReadCheckConfig(r io.Reader) (CheckConfig, error) {
+	var c CheckConfig
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&c); err != nil {
+		return CheckConfig{}, err
+	}
+	return c, c.compile()
+}
+
+// compile compiles the rule patterns that have not been compiled yet.
+func (c *CheckConfig) compile() error {
+	for i := range c.Rules {
+		if c.Rules[i].re != nil {
+			continue
+		}
+		re, err := regexp.Compile(c.Rules[i].Pattern)
+		if err != nil {
+			return fmt.Errorf("rule %d: %w", i, err)
+		}
+		c.Rules[i].re = re
+	}
+	return nil
+}
+
+// ThresholdsFor returns the thresholds that apply to the benchmark with the given full name.
+func (c CheckConfig) ThresholdsFor(name string) Thresholds {
+	t := c.Thresholds
+	for _, r := range c.Rules {
+		if r.re != nil && r.re.MatchString(name) {
+			t = t.Merge(r.Thresholds)
+		}
+	}
+	return t
+}
+
+// Violation is a benchmark metric that regressed beyond its threshold.
+type Violation struct {
+	// Name is the full benchmark name
+	Name string
+	// Unit is the regressed metric: ns/op, B/op or allocs/op
+	Unit string
+	// Old is the baseline value, the mean over repeated runs
+	Old float64
+	// New is the current value, the mean over repeated runs
+	New float64
+	// DeltaPct is the regression in percent, +Inf if the baseline was 0
+	DeltaPct float64
+	// Limit is the threshold that was exceeded
+	Limit Percent
+	// NeedSamples is the number of samples per side the significance check needs at the configured alpha.
+	// It is set when the runs had fewer, so the violation is reported on the means without telling noise apart.
+	NeedSamples int
+}
+
+// String describes the violation using the CPU[...] and MEM[...] notation, e.g.
+// "BenchmarkX-8: ns/op +12.00% > 5%: CPU[1µs] -> CPU[1µs 120ns]".
+// Violations that could not be tested for significance end with the number of samples needed.
+func (v Violation) String() string {
+	var o, n string
+	switch v.Unit {
+	case "ns/op":
+		o, n = "CPU["+HumanNsFloat(v.Old)+"]", "CPU["+HumanNsFloat(v.New)+"]"
+	case "B/op":
+		o, n = "MEM["+HumanValue("B/op", v.Old)+"]", "MEM["+HumanValue("B/op", v.New)+"]"
+	default:
+		o, n = HumanValue(v.Unit, v.Old)+" "+v.Unit, HumanValue(v.Unit, v.New)+" "+v.Unit
+	}
+	out := fmt.Sprintf("%s: %s %s > %s: %s -> %s", v.Name, v.Unit, FormatDeltaPct(v.DeltaPct), v.Limit, o, n)
+	if v.NeedSamples > 0 {
+		out += fmt.Sprintf(" (too few samples for significance, need ≥%d)", v.NeedSamples)
+	}
+	return out
+}
+
+// Check compares every benchmark present on both sides against its thresholds and returns the violations.
+// A metric whose baseline is 0 violates any threshold as soon as it grows.
+// Regressions of repeated runs that are not significant at Alpha are ignored, unless the runs have too few
+// samples for the test to ever reach Alpha; those are reported on the means with NeedSamples set.
+func (c CheckConfig) Check(comparisons []Comparison) ([]Violation, error) {
+	if err := c.compile(); err != nil {
+		return nil, err
+	}
+
+	var out []Violation
+	for _, cmp := range comparisons {
+		if cmp.Old == nil || cmp.New == nil {
+			continue
+		}
+		t := c.ThresholdsFor(cmp.Name)
+		for _, check := range []struct {
+			unit  string
+			limit *Percent
+		}{
+			{"ns/op", t.Time},
+			{"B/op", t.Mem},
+			{"allocs/op", t.Allocs},
+		} {
+			if check.limit == nil {
+				continue
+			}
+			o, n, ok := cmp.Means(check.unit)
+			if !ok || n <= o {
+				continue
+			}
+			pct, ok := deltaPct(o, n)
+			if !ok {
+				pct = math.Inf(1)
+			}
+			if pct <= float64(*check.limit) {
+				continue
+			}
+			need := 0
+			if p, ok := cmp.PValue(check.unit, MannWhitneyU); ok && c.Alpha != nil && *c.Alpha > 0 {
+				// With too few samples no outcome of the test reaches alpha, so it cannot tell noise from a regression.
+				if mannWhitneyMinP(len(cmp.Old.Values(check.unit)), len(cmp.New.Values(check.unit))) > *c.Alpha {
+					need = mannWhitneySamples(*c.Alpha)
+				} else if p > *c.Alpha {
+					continue
+				}
+			}
+			out = append(out, Violation{
+				Name:        cmp.Name,
+				Unit:        check.unit,
+				Old:         o,
+				New:         n,
+				DeltaPct:    pct,
+				Limit:       *check.limit,
+				NeedSamples: need,
+			})
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,171 @@
+package benchutil
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePercent(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Percent
+		err   bool
+	}{
+		{"5%", 5, false},
+		{"0", 0, false},
+		{" 2.5 % ", 2.5, false},
+		{"2.5", 2.5, false},
+		{"five", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParsePercent(tt.input)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("ParsePercent(%q) = %v, %v, want %v, error %v", tt.input, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestReadCheckConfig(t *testing.T) {
+	c, err := ReadCheckConfig(strings.NewReader(`{
+		"max-time-regress": "5%",
+		"max-alloc-regress": 0,
+		"rules": [
+			{"pattern": "^BenchmarkSample/", "max-time-regress": "20%"},
+			{"pattern": "Count1000-", "max-mem-regress": 10}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	th := c.ThresholdsFor("BenchmarkOther-8")
+	if th.Time == nil || *th.Time != 5 || th.Mem != nil || th.Allocs == nil || *th.Allocs != 0 {
+		t.Errorf("ThresholdsFor(BenchmarkOther-8) = %+v", th)
+	}
+	th = c.ThresholdsFor("BenchmarkSample/Count1000-8")
+	if *th.Time != 20 || th.Mem == nil || *th.Mem != 10 || *th.Allocs != 0 {
+		t.Errorf("ThresholdsFor(BenchmarkSample/Count1000-8) = %+v", th)
+	}
+
+	if c.Alpha != nil {
+		t.Errorf("Alpha = %v, want nil when not set", *c.Alpha)
+	}
+	if c, err := ReadCheckConfig(strings.NewReader(`{"alpha": 0}`)); err != nil || c.Alpha == nil || *c.Alpha != 0 {
+		t.Errorf(`ReadCheckConfig({"alpha": 0}).Alpha = %v, %v, want 0`, c.Alpha, err)
+	}
+
+	if _, err := ReadCheckConfig(strings.NewReader(`{"rules": [{"pattern": "("}]}`)); err == nil {
+		t.Errorf("expected an error for an invalid pattern")
+	}
+	if _, err := ReadCheckConfig(strings.NewReader(`{"max-time": "5%"}`)); err == nil {
+		t.Errorf("expected an error for an unknown field")
+	}
+}
+
+func TestCheckConfig_Check(t *testing.T) {
+	base := mustParseLines(t,
+		"BenchmarkA-8   100   1000 ns/op   1024 B/op   2 allocs/op",
+		"BenchmarkB-8   100   1000 ns/op   0 B/op   0 allocs/op",
+		"BenchmarkC-8   100   1000 ns/op",
+		"BenchmarkGone-8   100   1000 ns/op",
+	)
+	current := mustParseLines(t,
+		"BenchmarkA-8   100   1100 ns/op   1536 B/op   2 allocs/op",
+		"BenchmarkB-8   100   1040 ns/op   16 B/op   1 allocs/op",
+		"BenchmarkC-8   100   1500 ns/op",
+		"BenchmarkNew-8   100   1000 ns/op",
+	)
+
+	five, zero, sixty := Percent(5), Percent(0), Percent(60)
+	c := CheckConfig{
+		Thresholds: Thresholds{Time: &five, Allocs: &zero},
+		Rules:      []CheckRule{{Pattern: "^BenchmarkC", Thresholds: Thresholds{Time: &sixty}}},
+	}
+
+	got, err := c.Check(Compare(base, current))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"BenchmarkA-8: ns/op +10.00% > 5%: CPU[1µs] -> CPU[1µs 100ns]",
+		"BenchmarkB-8: allocs/op +Inf% > 0%: 0 allocs/op -> 1 allocs/op",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Check() returned %d violations, want %d: %v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i].String() != want[i] {
+			t.Errorf("violation %d = %q, want %q", i, got[i].String(), want[i])
+		}
+	}
+
+	c.Mem = &five
+	got, _ = c.Check(Compare(base, current))
+	if len(got) != 4 || got[1].String() != "BenchmarkA-8: B/op +50.00% > 5%: MEM[1KiB] -> MEM[1KiB 512B]" {
+		t.Errorf("Check() with mem threshold = %v", got)
+	}
+}
+
+func TestCheckConfig_CheckIgnoresNoise(t *testing.T) {
+	base := mustParseLines(t,
+		"BenchmarkA-8   100   1000 ns/op",
+		"BenchmarkA-8   100   1200 ns/op",
+		"BenchmarkA-8   100   900 ns/op",
+		"BenchmarkA-8   100   1050 ns/op",
+		"BenchmarkA-8   100   1100 ns/op",
+	)
+	current := mustParseLines(t,
+		"BenchmarkA-8   100   1150 ns/op",
+		"BenchmarkA-8   100   1000 ns/op",
+		"BenchmarkA-8   100   1250 ns/op",
+		"BenchmarkA-8   100   1050 ns/op",
+		"BenchmarkA-8   100   1100 ns/op",
+	)
+
+	five := Percent(5)
+	c := CheckConfig{Thresholds: Thresholds{Time: &five}}
+	if got, _ := c.Check(Compare(base, current)); len(got) != 1 {
+		t.Errorf("Check() without alpha = %v, want 1 violation", got)
+	}
+	alpha := 0.05
+	c.Alpha = &alpha
+	if got, _ := c.Check(Compare(base, current)); len(got) != 0 {
+		t.Errorf("Check() with alpha = %v, want no violations", got)
+	}
+}
+
+func TestCheckConfig_CheckTooFewSamples(t *testing.T) {
+	five := Percent(5)
+	alpha := 0.05
+	c := CheckConfig{Thresholds: Thresholds{Time: &five}, Alpha: &alpha}
+
+	// With 3+3 samples the smallest possible p-value is 0.1, so even an obvious regression is never significant.
+	base := mustParseLines(t,
+		"BenchmarkA-8   100   100 ns/op",
+		"BenchmarkA-8   100   101 ns/op",
+		"BenchmarkA-8   100   102 ns/op",
+	)
+	current := mustParseLines(t,
+		"BenchmarkA-8   100   500 ns/op",
+		"BenchmarkA-8   100   501 ns/op",
+		"BenchmarkA-8   100   502 ns/op",
+	)
+	got, err := c.Check(Compare(base, current))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].NeedSamples != 4 {
+		t.Fatalf("Check() = %+v, want 1 violation needing 4 samples", got)
+	}
+	if want := "BenchmarkA-8: ns/op +396.04% > 5%: CPU[101ns] -> CPU[501ns] (too few samples for significance, need ≥4)"; got[0].String() != want {
+		t.Errorf("String() = %q, want %q", got[0].String(), want)
+	}
+
+	// With 4+4 samples the same regression is significant and reported without a note.
+	base = append(base, mustParseLines(t, "BenchmarkA-8   100   103 ns/op")...)
+	current = append(current, mustParseLines(t, "BenchmarkA-8   100   503 ns/op")...)
+	if got, _ := c.Check(Compare(base, current)); len(got) != 1 || got[0].NeedSamples != 0 {
+		t.Errorf("Check() with 4+4 samples = %+v, want 1 significant violation", got)
+	}
+}
@@ -0,0 +1,121 @@
+package benchutil
+
+import (
+	"fmt"
+)
+
+// Comparison pairs the old and new runs of a benchmark matched by its full name.
+// Old or New is nil when the benchmark only appears in one of the inputs.
+type Comparison struct {
+	// Name is the full benchmark name, including the GOMAXPROCS suffix
+	Name string
+	// Old holds the runs from the old input, nil if the benchmark only appears in the new input
+	Old *SampleSet
+	// New holds the runs from the new input, nil if the benchmark only appears in the old input
+	New *SampleSet
+}
+
+// Compare matches the benchmarks of two runs by full name. Repeated lines of the same benchmark,
+// as produced by go test -count=N, are grouped into one SampleSet per side.
+// The result follows the order of the old run, with benchmarks that only appear in the new run appended
+// in their order.
+func Compare(old, new []BenchLine) []Comparison {
+	var out []Comparison
+	index := map[string]int{}
+
+	oldSets := GroupSamples(old)
+	for i := range oldSets {
+		index[oldSets[i].Name] = len(out)
+		out = append(out, Comparison{Name: oldSets[i].Name, Old: &oldSets[i]})
+	}
+	newSets := GroupSamples(new)
+	for i := range newSets {
+		j, ok := index[newSets[i].Name]
+		if !ok {
+			out = append(out, Comparison{Name: newSets[i].Name, New: &newSets[i]})
+			continue
+		}
+		out[j].New = &newSets[i]
+	}
+	return out
+}
+
+// Means returns the mean old and new value of the metric with the given unit.
+// The last return value is false if either side is missing the metric.
+func (c Comparison) Means(unit string) (old, new float64, ok bool) {
+	if c.Old == nil || c.New == nil {
+		return 0, 0, false
+	}
+	o, ok := c.Old.Summary(unit)
+	if !ok {
+		return 0, 0, false
+	}
+	n, ok := c.New.Summary(unit)
+	if !ok {
+		return 0, 0, false
+	}
+	return o.Mean, n.Mean, true
+}
+
+// DeltaPct calculates the percentage difference of the mean of the metric with the given unit
+// between the old and new runs.
+// Returns a positive value if the new value is higher, negative if it is lower.
+// The second return value is false if either side is missing the metric,
+// or if the old value is 0 while the new one is not.
+func (c Comparison) DeltaPct(unit string) (float64, bool) {
+	o, n, ok := c.Means(unit)
+	if !ok {
+		return 0, false
+	}
+	return deltaPct(o, n)
+}
+
+// PValue runs the hypothesis test on the old and new samples of the metric with the given unit.
+// The second return value is false if either side has fewer than two samples of the metric,
+// since a single run cannot tell a change from noise.
+func (c Comparison) PValue(unit string, test HypothesisTest) (float64, bool) {
+	if c.Old == nil || c.New == nil {
+		return 0, false
+	}
+	o, n := c.Old.Values(unit), c.New.Values(unit)
+	if len(o) < 2 || len(n) < 2 {
+		return 0, false
+	}
+	return test(o, n), true
+}
+
+// FormatDelta formats the delta of the metric with the given unit the way benchstat does.
+// If both sides have repeated samples, the hypothesis test decides whether the change is significant
+// at level alpha; insignificant changes are shown as "~". The p-value and sample counts follow in parentheses,
+// e.g. "-8.74% (p=0.008 n=5+5)" or "~ (p=0.421 n=5+5)".
+// Without repeated samples only the percentage is shown. If the delta cannot be computed, "-" is returned.
+func (c Comparison) FormatDelta(unit string, test HypothesisTest, alpha float64) string {
+	pct, ok := c.DeltaPct(unit)
+	if !ok {
+		return "-"
+	}
+	p, ok := c.PValue(unit, test)
+	if !ok {
+		return FormatDeltaPct(pct)
+	}
+
+	n := fmt.Sprintf("(p=%.3f n=%d+%d)", p, len(c.Old.Values(unit)), len(c.New.Values(unit)))
+	if p > alpha {
+		return "~ " + n
+	}
+	return FormatDeltaPct(pct) + " " + n
+}
+
+// deltaPct calculates the percentage change from a to b.
+// The second return value is false if a is 0 and b is not, since the change is unbounded.
+func deltaPct(a, b float64) (float64, bool) {
+	if a == 0 {
+		return 0, b == 0
+	}
+	return (b - a) / a * 100, true
+}
+
+// FormatDeltaPct formats a percentage delta with an explicit sign and two decimals, e.g. "+3.27%".
+func FormatDeltaPct(pct float64) string {
+	return fmt.Sprintf("%+.2f%%", pct)
+}
@@ -0,0 +1,113 @@
+package benchutil
+
+import (
+	"testing"
+)
+
+func mustParseLines(t *testing.T, lines ...string) []BenchLine {
+	t.Helper()
+	var out []BenchLine
+	for _, l := range lines {
+		bl, ok := ParseLine(l)
+		if !ok {
+			t.Fatalf("ParseLine(%q) failed", l)
+		}
+		out = append(out, bl)
+	}
+	return out
+}
+
+func TestCompare(t *testing.T) {
+	old := mustParseLines(t,
+		"BenchmarkA-8   100   900 ns/op   100 B/op   2 allocs/op",
+		"BenchmarkB-8   100   500 ns/op",
+		"BenchmarkA-8   100   1100 ns/op   100 B/op   2 allocs/op",
+	)
+	new := mustParseLines(t,
+		"BenchmarkC-8   100   10 ns/op",
+		"BenchmarkA-8   100   2000 ns/op   300 B/op   2 allocs/op",
+	)
+
+	got := Compare(old, new)
+	if len(got) != 3 {
+		t.Fatalf("Compare() returned %d comparisons, want 3", len(got))
+	}
+	if got[0].Name != "BenchmarkA-8" || got[0].Old == nil || got[0].New == nil || len(got[0].Old.Lines) != 2 {
+		t.Errorf("got[0] = %+v, want BenchmarkA-8 on both sides with 2 old samples", got[0])
+	}
+	if got[1].Name != "BenchmarkB-8" || got[1].New != nil {
+		t.Errorf("got[1] = %+v, want BenchmarkB-8 only in old", got[1])
+	}
+	if got[2].Name != "BenchmarkC-8" || got[2].Old != nil {
+		t.Errorf("got[2] = %+v, want BenchmarkC-8 only in new", got[2])
+	}
+
+	if pct, ok := got[0].DeltaPct("ns/op"); !ok || pct != 100 {
+		t.Errorf("DeltaPct(ns/op) = %v, %v, want 100, true", pct, ok)
+	}
+	if pct, ok := got[0].DeltaPct("B/op"); !ok || pct != 200 {
+		t.Errorf("DeltaPct(B/op) = %v, %v, want 200, true", pct, ok)
+	}
+	if pct, ok := got[0].DeltaPct("allocs/op"); !ok || pct != 0 {
+		t.Errorf("DeltaPct(allocs/op) = %v, %v, want 0, true", pct, ok)
+	}
+	if _, ok := got[1].DeltaPct("ns/op"); ok {
+		t.Errorf("DeltaPct on a one-sided comparison should not be ok")
+	}
+}
+
+func TestComparison_FormatDelta(t *testing.T) {
+	old := mustParseLines(t,
+		"BenchmarkA-8   100   1000 ns/op   18 allocs/op",
+		"BenchmarkA-8   100   1010 ns/op   18 allocs/op",
+		"BenchmarkA-8   100   990 ns/op   18 allocs/op",
+		"BenchmarkA-8   100   1005 ns/op   18 allocs/op",
+		"BenchmarkA-8   100   995 ns/op   18 allocs/op",
+		"BenchmarkB-8   100   500 ns/op",
+	)
+	new := mustParseLines(t,
+		"BenchmarkA-8   100   1100 ns/op   18 allocs/op",
+		"BenchmarkA-8   100   1110 ns/op   18 allocs/op",
+		"BenchmarkA-8   100   1090 ns/op   18 allocs/op",
+		"BenchmarkA-8   100   1105 ns/op   18 allocs/op",
+		"BenchmarkA-8   100   1095 ns/op   18 allocs/op",
+		"BenchmarkB-8   100   600 ns/op",
+	)
+	cmp := Compare(old, new)
+
+	tests := []struct {
+		c    Comparison
+		unit string
+		test HypothesisTest
+		want string
+	}{
+		{cmp[0], "ns/op", MannWhitneyU, "+10.00% (p=0.008 n=5+5)"},
+		{cmp[0], "ns/op", WelchTTest, "+10.00% (p=0.000 n=5+5)"},
+		{cmp[0], "allocs/op", MannWhitneyU, "~ (p=1.000 n=5+5)"},
+		{cmp[0], "B/op", MannWhitneyU, "-"},
+		{cmp[1], "ns/op", MannWhitneyU, "+20.00%"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.c.FormatDelta(tt.unit, tt.test, 0.05); got != tt.want {
+			t.Errorf("FormatDelta(%s, %s) = %q, want %q", tt.c.Name, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDeltaPct(t *testing.T) {
+	tests := []struct {
+		input float64
+		want  string
+	}{
+		{3.2749, "+3.27%"},
+		{-24.51, "-24.51%"},
+		{0, "+0.00%"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatDeltaPct(tt.input); got != tt.want {
+			t.Errorf("FormatDeltaPct(%v) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
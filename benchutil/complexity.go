@@ -0,0 +1,245 @@
+package benchutil
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Complexity is an asymptotic cost model fitted by FitComplexity.
+type Complexity int
+
+// Complexity models, from the slowest growing to the fastest.
+const (
+	// O1 is constant cost
+	O1 Complexity = iota
+	// OLogN is logarithmic cost
+	OLogN
+	// ON is linear cost
+	ON
+	// ONLogN is linearithmic cost
+	ONLogN
+	// ON2 is quadratic cost
+	ON2
+)
+
+// complexities lists every model in the order FitComplexity tries them.
+var complexities = []Complexity{O1, OLogN, ON, ONLogN, ON2}
+
+// String returns the big-O notation of the model, e.g. "O(n log n)".
+func (c Complexity) String() string {
+	switch c {
+	case O1:
+		return "O(1)"
+	case OLogN:
+		return "O(log n)"
+	case ON:
+		return "O(n)"
+	case ONLogN:
+		return "O(n log n)"
+	case ON2:
+		return "O(n²)"
+	default:
+		return fmt.Sprintf("Complexity(%d)", int(c))
+	}
+}
+
+// ParseComplexity parses a model in big-O notation. Spaces are ignored and "n^2" is accepted for "n²".
+func ParseComplexity(s string) (Complexity, error) {
+	norm := strings.ReplaceAll(strings.ReplaceAll(s, " ", ""), "^2", "²")
+	for _, c := range complexities {
+		if strings.ReplaceAll(c.String(), " ", "") == norm {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown complexity %q, want one of O(1), O(log n), O(n), O(n log n), O(n^2)", s)
+}
+
+// eval returns the growth term of the model at n.
+func (c Complexity) eval(n float64) float64 {
+	switch c {
+	case OLogN:
+		return math.Log2(n)
+	case ON:
+		return n
+	case ONLogN:
+		return n * math.Log2(n)
+	case ON2:
+		return n * n
+	default:
+		return 1
+	}
+}
+
+// ComplexityPoint is one measurement of a parameter sweep.
+type ComplexityPoint struct {
+	// N is the value of the swept parameter
+	N float64
+	// Value is the measured metric
+	Value float64
+}
+
+// ComplexityFit is the least-squares fit of one model, value = Coef * f(n).
+type ComplexityFit struct {
+	// Complexity is the fitted model
+	Complexity Complexity
+	// Coef is the cost per unit of the growth term, e.g. ns per n for O(n)
+	Coef float64
+	// R2 is the coefficient of determination of the fit. It is 0 for O(1), which only explains the mean.
+	R2 float64
+	// RMS is the root mean square of the residuals, the criterion used to select the best model
+	RMS float64
+}
+
+// ComplexityResult holds the fits of every model to a parameter sweep.
+type ComplexityResult struct {
+	// Fits holds one fit per model, ordered from O(1) to O(n²)
+	Fits []ComplexityFit
+	// Best is the fit with the smallest residuals; among equal fits the slower growing model wins
+	Best ComplexityFit
+	// PerElement is the least-squares cost per element, the Coef of the O(n) fit
+	PerElement float64
+}
+
+// FitComplexity fits every complexity model to the points, without an intercept as the cost is
+// expected to vanish with n. Points with the same N are all used, so repeated runs weigh in.
+// It returns an error if there are fewer than three distinct positive values of N.
+func FitComplexity(points []ComplexityPoint) (ComplexityResult, error) {
+	distinct := map[float64]bool{}
+	for _, p := range points {
+		if p.N <= 0 {
+			return ComplexityResult{}, fmt.Errorf("parameter value %v is not positive", p.N)
+		}
+		distinct[p.N] = true
+	}
+	if len(distinct) < 3 {
+		return ComplexityResult{}, fmt.Errorf("need at least 3 distinct parameter values, got %d", len(distinct))
+	}
+
+	var mean float64
+	for _, p := range points {
+		mean += p.Value
+	}
+	mean /= float64(len(points))
+	var ssTot float64
+	for _, p := range points {
+		ssTot += (p.Value - mean) * (p.Value - mean)
+	}
+
+	var res ComplexityResult
+	for i, c := range complexities {
+		var fy, ff float64
+		for _, p := range points {
+			f := c.eval(p.N)
+			fy += f * p.Value
+			ff += f * f
+		}
+		fit := ComplexityFit{Complexity: c}
+		if ff > 0 {
+			fit.Coef = fy / ff
+		}
+		var ssRes float64
+		for _, p := range points {
+			r := p.Value - fit.Coef*c.eval(p.N)
+			ssRes += r * r
+		}
+		fit.RMS = math.Sqrt(ssRes / float64(len(points)))
+		if ssTot > 0 {
+			fit.R2 = 1 - ssRes/ssTot
+		}
+
+		res.Fits = append(res.Fits, fit)
+		if c == ON {
+			res.PerElement = fit.Coef
+		}
+		if i == 0 || fit.RMS < res.Best.RMS {
+			res.Best = fit
+		}
+	}
+	return res, nil
+}
+
+// ComplexityAnalysis is the complexity of one parameter sweep of a benchmark metric.
+type ComplexityAnalysis struct {
+	// Name is the benchmark name with the swept segment replaced by a wildcard,
+	// e.g. "BenchmarkSample/Count*-8" or "BenchmarkEncode/size=*/codec=zstd-8"
+	Name string
+	// Key is the swept dimension
+	Key string
+	// Unit is the analyzed metric unit
+	Unit string
+	// Points are the measurements in input order
+	Points []ComplexityPoint
+	// Result holds the fitted models
+	Result ComplexityResult
+}
+
+// AnalyzeComplexity groups benchmark lines into parameter sweeps and fits complexity models to
+// the metric with the given unit. A sweep is formed by lines whose names only differ in the numeric
+// value of the dimension key. If key is empty, the dimension of the last name segment is used.
+// Sweeps with too few distinct values are skipped. Sweeps are returned in order of first appearance.
+func AnalyzeComplexity(lines []BenchLine, key, unit string) []ComplexityAnalysis {
+	var out []ComplexityAnalysis
+	index := map[string]int{}
+	for _, l := range lines {
+		m, ok := l.Metric(unit)
+		if !ok {
+			continue
+		}
+		name, d, ok := sweepName(l, key)
+		if !ok || !d.Numeric {
+			continue
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(out)
+			index[name] = i
+			out = append(out, ComplexityAnalysis{Name: name, Key: d.Key, Unit: unit})
+		}
+		out[i].Points = append(out[i].Points, ComplexityPoint{N: d.Number, Value: m.Value})
+	}
+
+	var fitted []ComplexityAnalysis
+	for _, a := range out {
+		res, err := FitComplexity(a.Points)
+		if err != nil {
+			continue
+		}
+		a.Result = res
+		fitted = append(fitted, a)
+	}
+	return fitted
+}
+
+// sweepName returns the name of the benchmark with the value of the swept dimension replaced by "*",
+// along with that dimension. If key is empty, the dimension of the last segment is swept, otherwise
+// the last dimension with that key. The third return value is false if there is no such dimension.
+func sweepName(l BenchLine, key string) (string, Dimension, bool) {
+	segs := append([]string{}, l.Sub...)
+	for i := len(segs) - 1; i >= 0; i-- {
+		if key == "" && i != len(segs)-1 {
+			break
+		}
+		parts := strings.Split(segs[i], ",")
+		for j := len(parts) - 1; j >= 0; j-- {
+			d, ok := ParseDimension(parts[j])
+			if !ok || (key != "" && !strings.EqualFold(d.Key, key)) {
+				continue
+			}
+			if strings.Contains(parts[j], "=") {
+				parts[j] = d.Key + "=*"
+			} else {
+				parts[j] = d.Key + "*"
+			}
+			segs[i] = strings.Join(parts, ",")
+
+			name := strings.Join(append([]string{l.Base}, segs...), "/")
+			if l.Procs > 0 {
+				name += "-" + strconv.Itoa(l.Procs)
+			}
+			return name, d, true
+		}
+	}
+	return "", Dimension{}, false
+}
@@ -0,0 +1,104 @@
+package benchutil
+
+import (
+	"testing"
+)
+
+func TestFitComplexity(t *testing.T) {
+	models := map[Complexity]func(n float64) float64{
+		O1:     func(n float64) float64 { return 500 },
+		OLogN:  func(n float64) float64 { return 20 * OLogN.eval(n) },
+		ON:     func(n float64) float64 { return 3 * n },
+		ONLogN: func(n float64) float64 { return 2 * ONLogN.eval(n) },
+		ON2:    func(n float64) float64 { return 0.5 * n * n },
+	}
+	for want, f := range models {
+		var points []ComplexityPoint
+		for _, n := range []float64{10, 100, 1000, 10000, 100000} {
+			points = append(points, ComplexityPoint{N: n, Value: f(n)})
+		}
+		res, err := FitComplexity(points)
+		if err != nil {
+			t.Fatalf("FitComplexity(%s): %v", want, err)
+		}
+		if res.Best.Complexity != want {
+			t.Errorf("FitComplexity(%s samples) best = %s", want, res.Best.Complexity)
+		}
+		if want != O1 && !approxEqual(res.Best.R2, 1, 1e-9) {
+			t.Errorf("FitComplexity(%s samples) R² = %v, want 1", want, res.Best.R2)
+		}
+		if len(res.Fits) != 5 {
+			t.Errorf("FitComplexity(%s samples) returned %d fits, want 5", want, len(res.Fits))
+		}
+	}
+}
+
+func TestFitComplexityNoisyLinear(t *testing.T) {
+	// fixed overhead plus 12ns per element, with a few percent of noise
+	points := []ComplexityPoint{
+		{10, 230}, {100, 1290}, {1000, 11800}, {10000, 122000}, {100000, 1190000},
+	}
+	res, err := FitComplexity(points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Best.Complexity != ON {
+		t.Errorf("best = %s, want O(n)", res.Best.Complexity)
+	}
+	if !approxEqual(res.PerElement, 11.9, 0.1) {
+		t.Errorf("PerElement = %v, want about 11.9", res.PerElement)
+	}
+}
+
+func TestFitComplexityErrors(t *testing.T) {
+	if _, err := FitComplexity([]ComplexityPoint{{10, 1}, {10, 2}, {100, 3}}); err == nil {
+		t.Error("FitComplexity with 2 distinct values succeeded, want error")
+	}
+	if _, err := FitComplexity([]ComplexityPoint{{0, 1}, {10, 2}, {100, 3}}); err == nil {
+		t.Error("FitComplexity with n=0 succeeded, want error")
+	}
+}
+
+func TestParseComplexity(t *testing.T) {
+	for in, want := range map[string]Complexity{
+		"O(1)": O1, "O(log n)": OLogN, "O(n)": ON, "O(nlogn)": ONLogN, "O(n log n)": ONLogN, "O(n^2)": ON2, "O(n²)": ON2,
+	} {
+		got, err := ParseComplexity(in)
+		if err != nil || got != want {
+			t.Errorf("ParseComplexity(%q) = %s, %v, want %s", in, got, err, want)
+		}
+	}
+	if _, err := ParseComplexity("O(2^n)"); err == nil {
+		t.Error("ParseComplexity(O(2^n)) succeeded, want error")
+	}
+}
+
+func TestAnalyzeComplexity(t *testing.T) {
+	lines := mustParseLines(t,
+		"BenchmarkSample/Count10-8   1000   120 ns/op   64 B/op",
+		"BenchmarkSample/Count100-8   1000   1200 ns/op   64 B/op",
+		"BenchmarkSample/Count1000-8   1000   12000 ns/op   64 B/op",
+		"BenchmarkSort/size=10/algo=quick-8   1000   100 ns/op",
+		"BenchmarkSort/size=100/algo=quick-8   1000   10000 ns/op",
+		"BenchmarkSort/size=1000/algo=quick-8   1000   1000000 ns/op",
+		"BenchmarkSort/size=10/algo=heap-8   1000   100 ns/op",
+	)
+
+	got := AnalyzeComplexity(lines, "", "ns/op")
+	if len(got) != 1 || got[0].Name != "BenchmarkSample/Count*-8" || got[0].Key != "Count" || got[0].Result.Best.Complexity != ON {
+		t.Fatalf("AnalyzeComplexity(last segment) = %+v, want one O(n) sweep of BenchmarkSample/Count*-8", got)
+	}
+	if got[0].Result.PerElement != 12 {
+		t.Errorf("PerElement = %v, want 12", got[0].Result.PerElement)
+	}
+
+	got = AnalyzeComplexity(lines, "size", "ns/op")
+	if len(got) != 1 || got[0].Name != "BenchmarkSort/size=*/algo=quick-8" || got[0].Result.Best.Complexity != ON2 {
+		t.Fatalf("AnalyzeComplexity(size) = %+v, want one O(n²) sweep of BenchmarkSort/size=*/algo=quick-8", got)
+	}
+
+	got = AnalyzeComplexity(lines, "", "B/op")
+	if len(got) != 1 || got[0].Result.Best.Complexity != O1 {
+		t.Fatalf("AnalyzeComplexity(B/op) = %+v, want one O(1) sweep", got)
+	}
+}
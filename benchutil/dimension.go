@@ -0,0 +1,153 @@
+package benchutil
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Dimension is a parameter encoded in a sub-benchmark name segment,
+// either as "key=value" (e.g. "size=1024", "codec=zstd") or as "KeyNNN" (e.g. "Count1000").
+type Dimension struct {
+	// Key is the parameter name, e.g. "size" or "Count"
+	Key string `json:"key"`
+	// Value is the parameter value as written in the name
+	Value string `json:"value"`
+	// Numeric is true if Value is a number
+	Numeric bool `json:"numeric"`
+	// Number is the numeric value of Value, 0 if it is not numeric
+	Number float64 `json:"number,omitempty"`
+}
+
+// String returns the dimension in key=value form.
+func (d Dimension) String() string {
+	return d.Key + "=" + d.Value
+}
+
+// ParseDimension extracts the dimension encoded in a single name segment.
+// "size=1024" yields key "size" and value "1024", "Count1000" yields key "Count" and value "1000".
+// The second return value is false if the segment carries no dimension.
+func ParseDimension(segment string) (Dimension, bool) {
+	if k, v, ok := strings.Cut(segment, "="); ok {
+		if k == "" {
+			return Dimension{}, false
+		}
+		return newDimension(k, v), true
+	}
+
+	i := len(segment)
+	for i > 0 && segment[i-1] >= '0' && segment[i-1] <= '9' {
+		i--
+	}
+	if i == 0 || i == len(segment) {
+		return Dimension{}, false
+	}
+	return newDimension(segment[:i], segment[i:]), true
+}
+
+// newDimension creates a dimension and detects whether its value is numeric.
+func newDimension(key, value string) Dimension {
+	d := Dimension{Key: key, Value: value}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		d.Numeric, d.Number = true, f
+	}
+	return d
+}
+
+// Dimensions returns the dimensions of every sub-benchmark segment, in name order.
+// The base name is not decomposed. Segments may hold several "key=value" pairs separated by commas.
+func (l BenchLine) Dimensions() []Dimension {
+	var out []Dimension
+	for _, seg := range l.Sub {
+		for _, part := range strings.Split(seg, ",") {
+			if d, ok := ParseDimension(part); ok {
+				out = append(out, d)
+			}
+		}
+	}
+	return out
+}
+
+// Dimension returns the dimension with the given key, compared case-insensitively.
+// If the key appears in several segments, the last one wins.
+// The second return value is false if the name has no such dimension.
+func (l BenchLine) Dimension(key string) (Dimension, bool) {
+	var out Dimension
+	found := false
+	for _, d := range l.Dimensions() {
+		if strings.EqualFold(d.Key, key) {
+			out, found = d, true
+		}
+	}
+	return out, found
+}
+
+// ParseDimensionFilter parses a filter of the form "key=value[,key=value...]".
+func ParseDimensionFilter(s string) (map[string]string, error) {
+	out := map[string]string{}
+	for _, part := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid dimension filter %q, want key=value", part)
+		}
+		out[k] = v
+	}
+	return out, nil
+}
+
+// MatchDimensions reports whether the benchmark has every dimension of the filter with the given value.
+// Numeric values match numerically, so "size=1024" matches "size=1024.0".
+func (l BenchLine) MatchDimensions(filter map[string]string) bool {
+	for k, v := range filter {
+		d, ok := l.Dimension(k)
+		if !ok {
+			return false
+		}
+		if d.Value == v {
+			continue
+		}
+		want := newDimension(k, v)
+		if !d.Numeric || !want.Numeric || d.Number != want.Number {
+			return false
+		}
+	}
+	return true
+}
+
+// DimensionValues returns the distinct values of the dimension with the given key across the lines.
+// Numeric values are sorted numerically and come first, other values follow in order of first appearance.
+func DimensionValues(lines []BenchLine, key string) []Dimension {
+	var numeric, other []Dimension
+	seen := map[string]bool{}
+	for _, l := range lines {
+		d, ok := l.Dimension(key)
+		if !ok || seen[d.Value] {
+			continue
+		}
+		seen[d.Value] = true
+		if d.Numeric {
+			numeric = append(numeric, d)
+		} else {
+			other = append(other, d)
+		}
+	}
+	sort.SliceStable(numeric, func(i, j int) bool { return numeric[i].Number < numeric[j].Number })
+	return append(numeric, other...)
+}
+
+// GroupBySubBenchmark groups records by BenchLine.Group, i.e. by sub-benchmark prefix.
+func GroupBySubBenchmark(r Record) string {
+	return r.Group()
+}
+
+// GroupByDimension returns a grouping function that groups records by the value of the dimension with the given key,
+// as "key=value". Records without the dimension are grouped under "key=(none)".
+func GroupByDimension(key string) func(Record) string {
+	return func(r Record) string {
+		if d, ok := r.Dimension(key); ok {
+			return key + "=" + d.Value
+		}
+		return key + "=(none)"
+	}
+}
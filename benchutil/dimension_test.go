@@ -0,0 +1,138 @@
+package benchutil
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestParseDimension(t *testing.T) {
+	tests := []struct {
+		segment string
+		want    Dimension
+		ok      bool
+	}{
+		{"size=1024", Dimension{Key: "size", Value: "1024", Numeric: true, Number: 1024}, true},
+		{"codec=zstd", Dimension{Key: "codec", Value: "zstd"}, true},
+		{"ratio=0.5", Dimension{Key: "ratio", Value: "0.5", Numeric: true, Number: 0.5}, true},
+		{"empty=", Dimension{Key: "empty", Value: ""}, true},
+		{"Count1000", Dimension{Key: "Count", Value: "1000", Numeric: true, Number: 1000}, true},
+		{"Parallel", Dimension{}, false},
+		{"1024", Dimension{}, false},
+		{"=5", Dimension{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseDimension(tt.segment)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseDimension(%q) = %+v, %v, want %+v, %v", tt.segment, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestBenchLineDimensions(t *testing.T) {
+	lines := mustParseLines(t,
+		"BenchmarkEncode/size=1024/codec=zstd-8   1000   1500 ns/op",
+		"BenchmarkSample/Count1000/mode=a,level=3-8   1000   1500 ns/op",
+		"BenchmarkPlain10-8   1000   1500 ns/op",
+	)
+
+	if got := lines[0].Segments(); !reflect.DeepEqual(got, []string{"BenchmarkEncode", "size=1024", "codec=zstd"}) {
+		t.Errorf("Segments() = %q", got)
+	}
+
+	var keys []string
+	for _, d := range lines[1].Dimensions() {
+		keys = append(keys, d.String())
+	}
+	if want := []string{"Count=1000", "mode=a", "level=3"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("Dimensions() = %q, want %q", keys, want)
+	}
+	if got := lines[2].Dimensions(); got != nil {
+		t.Errorf("Dimensions() of a name without sub-benchmarks = %+v, want nil", got)
+	}
+
+	if d, ok := lines[0].Dimension("CODEC"); !ok || d.Value != "zstd" {
+		t.Errorf("Dimension(CODEC) = %+v, %v, want zstd", d, ok)
+	}
+	if _, ok := lines[0].Dimension("level"); ok {
+		t.Error("Dimension(level) found on a name without it")
+	}
+}
+
+func TestMatchDimensions(t *testing.T) {
+	bl := mustParseLines(t, "BenchmarkEncode/size=1024/codec=zstd-8   1000   1500 ns/op")[0]
+
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{"size=1024", true},
+		{"size=1024.0", true},
+		{"size=1024,codec=zstd", true},
+		{"codec=gzip", false},
+		{"level=1", false},
+	}
+	for _, tt := range tests {
+		filter, err := ParseDimensionFilter(tt.filter)
+		if err != nil {
+			t.Fatalf("ParseDimensionFilter(%q): %v", tt.filter, err)
+		}
+		if got := bl.MatchDimensions(filter); got != tt.want {
+			t.Errorf("MatchDimensions(%q) = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+
+	if _, err := ParseDimensionFilter("size"); err == nil {
+		t.Error("ParseDimensionFilter(size) succeeded, want error")
+	}
+}
+
+func TestDimensionValues(t *testing.T) {
+	lines := mustParseLines(t,
+		"BenchmarkEncode/size=4096/codec=zstd-8   1000   1500 ns/op",
+		"BenchmarkEncode/size=64/codec=gzip-8   1000   1500 ns/op",
+		"BenchmarkEncode/size=1024/codec=zstd-8   1000   1500 ns/op",
+		"BenchmarkEncode/size=big/codec=zstd-8   1000   1500 ns/op",
+	)
+
+	var got []string
+	for _, d := range DimensionValues(lines, "size") {
+		got = append(got, d.Value)
+	}
+	if want := []string{"64", "1024", "4096", "big"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("DimensionValues(size) = %q, want %q", got, want)
+	}
+}
+
+func TestMarkdownGroupByDimension(t *testing.T) {
+	f := NewFormatter()
+	var buf bytes.Buffer
+	w := NewMarkdownWriter(&buf, GroupByDimension("codec"))
+	for _, l := range []string{
+		"BenchmarkEncode/size=64/codec=zstd-8   1000   1500 ns/op",
+		"BenchmarkEncode/size=64/codec=gzip-8   1000   2500 ns/op",
+		"BenchmarkEncode/size=1024/codec=zstd-8   1000   3500 ns/op",
+	} {
+		_ = w.Write(f.Record(l))
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `### codec=zstd
+
+| Benchmark                              | Iterations | ns/op | CPU       | B/op | MEM | allocs/op |
+| -------------------------------------- | ---------: | ----: | --------: | ---: | --: | --------: |
+| BenchmarkEncode/size=64/codec=zstd-8   |       1000 |  1500 | 1µs 500ns |      |     |           |
+| BenchmarkEncode/size=1024/codec=zstd-8 |       1000 |  3500 | 3µs 500ns |      |     |           |
+
+### codec=gzip
+
+| Benchmark                            | Iterations | ns/op | CPU       | B/op | MEM | allocs/op |
+| ------------------------------------ | ---------: | ----: | --------: | ---: | --: | --------: |
+| BenchmarkEncode/size=64/codec=gzip-8 |       1000 |  2500 | 2µs 500ns |      |     |           |
+`
+	if buf.String() != want {
+		t.Errorf("markdown output grouped by codec:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
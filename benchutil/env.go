@@ -0,0 +1,113 @@
+package benchutil
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Env is the environment header go test -bench prints before the benchmarks of a package.
+type Env struct {
+	// GOOS is the value of the "goos:" header line
+	GOOS string `json:"goos,omitempty"`
+	// GOARCH is the value of the "goarch:" header line
+	GOARCH string `json:"goarch,omitempty"`
+	// Pkg is the value of the "pkg:" header line
+	Pkg string `json:"pkg,omitempty"`
+	// CPU is the value of the "cpu:" header line
+	CPU string `json:"cpu,omitempty"`
+}
+
+// ParseEnvLine parses a single header line into its key and value.
+// The second return value is false if the line is not a goos, goarch, pkg or cpu header line.
+func ParseEnvLine(line string) (key, value string, ok bool) {
+	k, v, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", "", false
+	}
+	switch k {
+	case "goos", "goarch", "pkg", "cpu":
+		return k, strings.TrimSpace(v), true
+	}
+	return "", "", false
+}
+
+// set sets the field for the given header key.
+func (e *Env) set(key, value string) {
+	switch key {
+	case "goos":
+		e.GOOS = value
+	case "goarch":
+		e.GOARCH = value
+	case "pkg":
+		e.Pkg = value
+	case "cpu":
+		e.CPU = value
+	}
+}
+
+// Compatible reports whether benchmarks from e and o can be meaningfully compared.
+// It returns an error describing every difference in GOOS, GOARCH and CPU.
+// Fields that are empty on either side are not compared, and the package is ignored.
+func (e Env) Compatible(o Env) error {
+	var diffs []string
+	for _, f := range []struct{ name, a, b string }{
+		{"goos", e.GOOS, o.GOOS},
+		{"goarch", e.GOARCH, o.GOARCH},
+		{"cpu", e.CPU, o.CPU},
+	} {
+		if f.a != "" && f.b != "" && f.a != f.b {
+			diffs = append(diffs, fmt.Sprintf("%s %q != %q", f.name, f.a, f.b))
+		}
+	}
+	if len(diffs) > 0 {
+		return fmt.Errorf("different environments: %s", strings.Join(diffs, ", "))
+	}
+	return nil
+}
+
+// FirstEnv returns the environment of the first benchmark record that has one, or nil.
+func FirstEnv(records []Record) *Env {
+	for _, r := range records {
+		if r.Env != nil {
+			return r.Env
+		}
+	}
+	return nil
+}
+
+// Stream converts consecutive lines of go test -bench output into Records.
+// It tracks the goos/goarch/pkg/cpu header block and attaches it to every following benchmark record,
+// until the header block of the next package, which go test prints before its pkg: line.
+type Stream struct {
+	f        *Formatter
+	env      *Env
+	sawBench bool
+}
+
+// NewStream returns a Stream that converts lines with the Formatter.
+// Use one Stream per input, since the environment header carries over between lines.
+func (f *Formatter) NewStream() *Stream {
+	return &Stream{f: f}
+}
+
+// Record converts the next line of output into a Record, as Formatter.Record does,
+// and attaches the current environment header to benchmark records.
+func (s *Stream) Record(line string) Record {
+	r := s.f.Record(line)
+	if r.Type == RecordBenchmark {
+		s.sawBench = true
+		r.Env = s.env
+		return r
+	}
+
+	if k, v, ok := ParseEnvLine(line); ok {
+		// A header line after benchmarks starts the header block of the next package.
+		// Records only point to an Env once a benchmark follows it, so the current one can be filled in place.
+		if s.env == nil || s.sawBench {
+			s.env = &Env{}
+			s.sawBench = false
+		}
+		s.env.set(k, v)
+	}
+	return r
+}
@@ -0,0 +1,85 @@
+package benchutil
+
+import (
+	"testing"
+)
+
+func TestStream_Env(t *testing.T) {
+	s := NewFormatter().NewStream()
+	lines := []string{
+		"BenchmarkNoEnv-8   10   5 ns/op",
+		"goos: linux",
+		"goarch: amd64",
+		"pkg: example.com/a",
+		"cpu: Test CPU",
+		"BenchmarkA-8   10   5 ns/op",
+		"BenchmarkA2-8   10   5 ns/op",
+		"PASS",
+		"ok  	example.com/a	1.0s",
+		"goos: linux",
+		"goarch: arm64",
+		"pkg: example.com/b",
+		"BenchmarkB-8   10   5 ns/op",
+	}
+	var records []Record
+	for _, l := range lines {
+		records = append(records, s.Record(l))
+	}
+
+	if records[0].Env != nil {
+		t.Errorf("benchmark before any header has Env %+v, want nil", records[0].Env)
+	}
+	want := Env{GOOS: "linux", GOARCH: "amd64", Pkg: "example.com/a", CPU: "Test CPU"}
+	for _, i := range []int{5, 6} {
+		if records[i].Env == nil || *records[i].Env != want {
+			t.Errorf("record %d Env = %+v, want %+v", i, records[i].Env, want)
+		}
+	}
+	want = Env{GOOS: "linux", GOARCH: "arm64", Pkg: "example.com/b"}
+	if records[12].Env == nil || *records[12].Env != want {
+		t.Errorf("record 12 Env = %+v, want %+v", records[12].Env, want)
+	}
+	if records[1].Type != RecordLog || records[1].Env != nil {
+		t.Errorf("header lines must stay log records without Env")
+	}
+	if env := FirstEnv(records); env == nil || env.Pkg != "example.com/a" {
+		t.Errorf("FirstEnv() = %+v, want example.com/a", env)
+	}
+}
+
+func TestParseEnvLine(t *testing.T) {
+	tests := []struct {
+		input string
+		key   string
+		value string
+		ok    bool
+	}{
+		{"goos: linux", "goos", "linux", true},
+		{"cpu: Intel(R) Core(TM) i7-8550U CPU @ 1.80GHz", "cpu", "Intel(R) Core(TM) i7-8550U CPU @ 1.80GHz", true},
+		{"pkg: github.com/rah-0/testmark", "pkg", "github.com/rah-0/testmark", true},
+		{"note: something", "", "", false},
+		{"PASS", "", "", false},
+	}
+
+	for _, tt := range tests {
+		k, v, ok := ParseEnvLine(tt.input)
+		if k != tt.key || v != tt.value || ok != tt.ok {
+			t.Errorf("ParseEnvLine(%q) = %q, %q, %v, want %q, %q, %v", tt.input, k, v, ok, tt.key, tt.value, tt.ok)
+		}
+	}
+}
+
+func TestEnv_Compatible(t *testing.T) {
+	a := Env{GOOS: "linux", GOARCH: "amd64", Pkg: "a", CPU: "X"}
+	if err := a.Compatible(Env{GOOS: "linux", GOARCH: "amd64", Pkg: "b", CPU: "X"}); err != nil {
+		t.Errorf("different packages should be compatible: %v", err)
+	}
+	if err := a.Compatible(Env{GOOS: "linux"}); err != nil {
+		t.Errorf("missing fields should be ignored: %v", err)
+	}
+	err := a.Compatible(Env{GOOS: "linux", GOARCH: "arm64", CPU: "Y"})
+	want := `different environments: goarch "amd64" != "arm64", cpu "X" != "Y"`
+	if err == nil || err.Error() != want {
+		t.Errorf("Compatible() = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,243 @@
+package benchutil
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Record types used in Record.Type.
+const (
+	// RecordBenchmark marks a record parsed from a benchmark line
+	RecordBenchmark = "benchmark"
+	// RecordLog marks a record holding a non-benchmark line, preserved as-is
+	RecordLog = "log"
+)
+
+// Record is a typed, export-ready representation of a single line of benchmark output.
+// Benchmark lines carry the parsed BenchLine and its human-readable forms,
+// every other line is kept as a log record holding the original text.
+type Record struct {
+	// Type is RecordBenchmark or RecordLog
+	Type string `json:"type"`
+	// Text is the original line of a log record
+	Text string `json:"text,omitempty"`
+	// BenchLine is the parsed benchmark line, nil for log records
+	*BenchLine
+	// CPU is ns/op formatted with the Formatter's Style, HumanNsFloat by default
+	CPU string `json:"cpu,omitempty"`
+	// MEM is B/op formatted with the Formatter's Style, HumanBytes by default
+	MEM string `json:"mem,omitempty"`
+	// Annotations are the annotations the Formatter appends in text output
+	Annotations []Annotation `json:"annotations,omitempty"`
+	// Dimensions are the key=value and KeyNNN parameters of the sub-benchmark name
+	Dimensions []Dimension `json:"dimensions,omitempty"`
+	// Env is the environment header the benchmark ran under, set by Stream
+	Env *Env `json:"env,omitempty"`
+	// GroupKey is the group the benchmark was assigned to by NewGroupWriter, empty if it was not grouped
+	GroupKey string `json:"group,omitempty"`
+}
+
+// Record converts a line of benchmark output into a Record.
+// Lines that ParseLine rejects become log records.
+func (f *Formatter) Record(line string) Record {
+	bl, ok := ParseLine(line)
+	if !ok {
+		return Record{Type: RecordLog, Text: line}
+	}
+
+	r := Record{
+		Type:        RecordBenchmark,
+		BenchLine:   &bl,
+		Annotations: f.Annotate(bl),
+		Dimensions:  bl.Dimensions(),
+	}
+	if m, ok := bl.Metric("ns/op"); ok {
+		r.CPU = f.style.NsFloat(m.Value)
+	}
+	if m, ok := bl.Metric("B/op"); ok {
+		r.MEM = f.style.Value("B/op", m.Value)
+	}
+	return r
+}
+
+// RecordWriter writes a stream of records in a specific output format.
+// Flush must be called after the last record, since some formats buffer their output.
+type RecordWriter interface {
+	Write(r Record) error
+	Flush() error
+}
+
+// textWriter writes records in testmark's tab-separated text format.
+type textWriter struct {
+	w io.Writer
+	f *Formatter
+	// group is the GroupKey of the previous record, empty after a log record
+	group string
+}
+
+// NewTextWriter returns a RecordWriter that prints benchmark records with the given Formatter
+// and log records unchanged, one line per record.
+// Every run of benchmark records with the same non-empty GroupKey is preceded by a "group: <key>" line.
+func NewTextWriter(w io.Writer, f *Formatter) RecordWriter {
+	return &textWriter{w: w, f: f}
+}
+
+func (t *textWriter) Write(r Record) error {
+	line := r.Text
+	if r.Type == RecordBenchmark {
+		line = t.f.FormatLine(*r.BenchLine)
+		if r.GroupKey != "" && r.GroupKey != t.group {
+			if _, err := fmt.Fprintln(t.w, groupHeader(r.GroupKey)); err != nil {
+				return err
+			}
+		}
+	}
+	t.group = r.GroupKey
+	_, err := fmt.Fprintln(t.w, line)
+	return err
+}
+
+// groupHeader returns the line that text output prints before a group of benchmarks.
+func groupHeader(key string) string {
+	return "group: " + key
+}
+
+func (t *textWriter) Flush() error {
+	return nil
+}
+
+// jsonWriter writes records as JSON, either one object per line or a single array document.
+type jsonWriter struct {
+	w       io.Writer
+	enc     *json.Encoder
+	doc     bool
+	records []Record
+}
+
+// NewJSONWriter returns a RecordWriter that encodes records as JSON.
+// If doc is false, every record is written as soon as it arrives as one JSON object per line.
+// If doc is true, records are buffered and written on Flush as a single JSON array.
+func NewJSONWriter(w io.Writer, doc bool) RecordWriter {
+	return &jsonWriter{w: w, enc: json.NewEncoder(w), doc: doc}
+}
+
+func (j *jsonWriter) Write(r Record) error {
+	if j.doc {
+		j.records = append(j.records, r)
+		return nil
+	}
+	return j.enc.Encode(r)
+}
+
+func (j *jsonWriter) Flush() error {
+	if !j.doc {
+		return nil
+	}
+	records := j.records
+	if records == nil {
+		records = []Record{}
+	}
+	j.enc.SetIndent("", "  ")
+	return j.enc.Encode(records)
+}
+
+// csvStandardUnits are the metric units that always have their own CSV column.
+var csvStandardUnits = []string{"ns/op", "B/op", "allocs/op"}
+
+// csvWriter writes benchmark records as CSV or TSV with a header row.
+type csvWriter struct {
+	w       *csv.Writer
+	records []Record
+}
+
+// NewCSVWriter returns a RecordWriter that writes benchmark records as delimiter-separated values.
+// Use ',' for CSV and '\t' for TSV. Log records are skipped.
+// Records are buffered and written on Flush, so the header can list every custom metric unit.
+// The header is: name, base, sub, procs, iterations, ns/op, B/op, allocs/op,
+// each custom unit in order of first appearance, cpu, mem, and the environment header
+// as goos, goarch, pkg and cpu_model.
+// The sub column holds the sub-benchmark segments joined by "/".
+// If any record has a GroupKey, a leading group column holds it.
+func NewCSVWriter(w io.Writer, comma rune) RecordWriter {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	return &csvWriter{w: cw}
+}
+
+func (c *csvWriter) Write(r Record) error {
+	if r.Type == RecordBenchmark {
+		c.records = append(c.records, r)
+	}
+	return nil
+}
+
+func (c *csvWriter) Flush() error {
+	custom := customUnits(c.records)
+	grouped := false
+	for _, r := range c.records {
+		grouped = grouped || r.GroupKey != ""
+	}
+	var header []string
+	if grouped {
+		header = append(header, "group")
+	}
+	header = append(header, "name", "base", "sub", "procs", "iterations")
+	header = append(header, csvStandardUnits...)
+	header = append(header, custom...)
+	header = append(header, "cpu", "mem", "goos", "goarch", "pkg", "cpu_model")
+	if err := c.w.Write(header); err != nil {
+		return err
+	}
+
+	units := append(append([]string{}, csvStandardUnits...), custom...)
+	for _, r := range c.records {
+		var row []string
+		if grouped {
+			row = append(row, r.GroupKey)
+		}
+		row = append(row,
+			r.Name,
+			r.Base,
+			strings.Join(r.Sub, "/"),
+			strconv.Itoa(r.Procs),
+			strconv.FormatInt(r.Iterations, 10),
+		)
+		for _, u := range units {
+			row = append(row, metricRaw(r, u))
+		}
+		row = append(row, r.CPU, r.MEM)
+		env := Env{}
+		if r.Env != nil {
+			env = *r.Env
+		}
+		row = append(row, env.GOOS, env.GOARCH, env.Pkg, env.CPU)
+		if err := c.w.Write(row); err != nil {
+			return err
+		}
+	}
+
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// customUnits returns the units of all non-standard metrics in the records, in order of first appearance.
+func customUnits(records []Record) []string {
+	seen := map[string]bool{}
+	for _, u := range csvStandardUnits {
+		seen[u] = true
+	}
+	var out []string
+	for _, r := range records {
+		for _, m := range r.Metrics {
+			if !seen[m.Unit] {
+				seen[m.Unit] = true
+				out = append(out, m.Unit)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,119 @@
+package benchutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestFormatter_Record(t *testing.T) {
+	f := NewFormatter()
+
+	r := f.Record("BenchmarkSample/Count1000-8   100000   248419 ns/op   1048 B/op   18 allocs/op")
+	if r.Type != RecordBenchmark {
+		t.Fatalf("Type = %q, want %q", r.Type, RecordBenchmark)
+	}
+	if r.Base != "BenchmarkSample" || r.Procs != 8 || r.Iterations != 100000 {
+		t.Errorf("unexpected parsed fields: %+v", r.BenchLine)
+	}
+	if r.CPU != "248µs 419ns" || r.MEM != "1KiB 24B" {
+		t.Errorf("CPU, MEM = %q, %q, want %q, %q", r.CPU, r.MEM, "248µs 419ns", "1KiB 24B")
+	}
+
+	r = f.Record("BenchmarkHuge-8   1   1 ns/op   1e30 B/op")
+	if want := HumanBytes(math.MaxInt64); r.MEM != want {
+		t.Errorf("MEM = %q, want %q clamped to the int64 range", r.MEM, want)
+	}
+
+	r = f.Record("PASS")
+	if r.Type != RecordLog || r.Text != "PASS" || r.BenchLine != nil {
+		t.Errorf("Record(PASS) = %+v, want log record", r)
+	}
+}
+
+func TestJSONWriter(t *testing.T) {
+	f := NewFormatter()
+	lines := []string{
+		"goos: linux",
+		"BenchmarkFast-8   1000   1500 ns/op   512 B/op",
+	}
+
+	var buf bytes.Buffer
+	w := NewJSONWriter(&buf, false)
+	for _, l := range lines {
+		if err := w.Write(f.Record(l)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"type":"log","text":"goos: linux"}
+{"type":"benchmark","name":"BenchmarkFast-8","base":"BenchmarkFast","procs":8,"iterations":1000,"metrics":[{"value":1500,"raw":"1500","unit":"ns/op"},{"value":512,"raw":"512","unit":"B/op"}],"cpu":"1µs 500ns","mem":"512B","annotations":[{"label":"CPU","unit":"ns/op","human":"1µs 500ns"}]}
+`
+	if buf.String() != want {
+		t.Errorf("JSON lines output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	w = NewJSONWriter(&buf, true)
+	for _, l := range lines {
+		if err := w.Write(f.Record(l)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if buf.Len() != 0 {
+		t.Errorf("document mode wrote before Flush: %q", buf.String())
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	var records []Record
+	if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+		t.Fatalf("invalid JSON document: %v", err)
+	}
+	if len(records) != 2 || records[1].Name != "BenchmarkFast-8" {
+		t.Errorf("unexpected document: %+v", records)
+	}
+}
+
+func TestCSVWriter(t *testing.T) {
+	s := NewFormatter().NewStream()
+	lines := []string{
+		"goos: linux",
+		"BenchmarkEncode/size=1024/codec=zstd-8   1000   1500 ns/op   12.50 MB/s   2048 B/op   3 allocs/op",
+		"BenchmarkDecode-8   500   800 ns/op   4 p99-ns",
+		"PASS",
+	}
+
+	var buf bytes.Buffer
+	w := NewCSVWriter(&buf, ',')
+	for _, l := range lines {
+		if err := w.Write(s.Record(l)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	want := `name,base,sub,procs,iterations,ns/op,B/op,allocs/op,MB/s,p99-ns,cpu,mem,goos,goarch,pkg,cpu_model
+BenchmarkEncode/size=1024/codec=zstd-8,BenchmarkEncode,size=1024/codec=zstd,8,1000,1500,2048,3,12.50,,1µs 500ns,2KiB,linux,,,
+BenchmarkDecode-8,BenchmarkDecode,,8,500,800,,,,4,800ns,,linux,,,
+`
+	if buf.String() != want {
+		t.Errorf("CSV output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	w = NewCSVWriter(&buf, '\t')
+	_ = w.Write(NewFormatter().Record(lines[2]))
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	want = "name\tbase\tsub\tprocs\titerations\tns/op\tB/op\tallocs/op\tp99-ns\tcpu\tmem\tgoos\tgoarch\tpkg\tcpu_model\n" +
+		"BenchmarkDecode-8\tBenchmarkDecode\t\t8\t500\t800\t\t\t4\t800ns\t\t\t\t\t\n"
+	if buf.String() != want {
+		t.Errorf("TSV output:\n%q\nwant:\n%q", buf.String(), want)
+	}
+}
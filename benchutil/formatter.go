@@ -0,0 +1,92 @@
+package benchutil
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Formatter renders parsed benchmark lines in testmark's text format.
+// The zero value is not ready for use; create one with NewFormatter.
+type Formatter struct {
+	// opsPerSec enables the derived OPS[...] annotation computed from ns/op
+	opsPerSec bool
+	// allocRate enables the derived ALLOC[...] annotation computed from B/op and ns/op
+	allocRate bool
+	// style selects how durations and byte sizes are written
+	style Style
+}
+
+// NewFormatter creates a new Formatter with default settings.
+// By default, only annotations for metrics present on the line are produced.
+func NewFormatter() *Formatter {
+	return &Formatter{}
+}
+
+// SetOpsPerSec enables or disables the derived operations-per-second annotation, e.g. "OPS[8.73K/s]".
+// Returns the Formatter instance for method chaining.
+func (f *Formatter) SetOpsPerSec(enabled bool) *Formatter {
+	f.opsPerSec = enabled
+	return f
+}
+
+// SetAllocRate enables or disables the derived allocation rate annotation, e.g. "ALLOC[1.2GB/s]",
+// which shows how many bytes the benchmark allocates per second of run time.
+// Returns the Formatter instance for method chaining.
+func (f *Formatter) SetAllocRate(enabled bool) *Formatter {
+	f.allocRate = enabled
+	return f
+}
+
+// SetStyle sets how durations and byte sizes are written in annotations and in the CPU and MEM fields of records,
+// e.g. Style{Compact: true} for "CPU[3.65ms]".
+// Returns the Formatter instance for method chaining.
+func (f *Formatter) SetStyle(style Style) *Formatter {
+	f.style = style
+	return f
+}
+
+// Style returns the style set with SetStyle.
+func (f *Formatter) Style() Style {
+	return f.style
+}
+
+// FormatLine renders a parsed benchmark line.
+// The name, iteration count and every metric are joined by tabs and followed by
+// the annotations returned by Annotate.
+func (f *Formatter) FormatLine(bl BenchLine) string {
+	parts := []string{bl.Name, strconv.FormatInt(bl.Iterations, 10)}
+	for _, m := range bl.Metrics {
+		parts = append(parts, m.Raw+" "+m.Unit)
+	}
+	for _, a := range f.Annotate(bl) {
+		parts = append(parts, a.String())
+	}
+	return strings.Join(parts, "\t")
+}
+
+// Annotate returns the human-readable annotations for the metrics of a benchmark line, in metric order,
+// followed by the enabled derived annotations.
+// ns/op is labeled CPU, B/op is labeled MEM and byte rates such as MB/s are labeled IO.
+// Any other unit whose leading quantity is a time unit (ns, µs, ms, s) or a byte unit
+// (B, KB, MB, GB, TB, PB, EB, KiB, MiB, GiB, TiB, PiB, EiB) is labeled with its own unit.
+// Annotations that would add nothing over the raw value, such as "512B" for "512 B/op", are omitted.
+func (f *Formatter) Annotate(bl BenchLine) []Annotation {
+	var out []Annotation
+	for _, m := range bl.Metrics {
+		if a, ok := annotate(m, f.style); ok {
+			out = append(out, a)
+		}
+	}
+
+	nsOp, hasNsOp := bl.Metric("ns/op")
+	if !hasNsOp || nsOp.Value <= 0 {
+		return out
+	}
+	if f.opsPerSec {
+		out = append(out, Annotation{Label: "OPS", Unit: "ops/s", Human: HumanOpsPerSec(1e9 / nsOp.Value)})
+	}
+	if bOp, ok := bl.Metric("B/op"); ok && f.allocRate && bOp.Value > 0 {
+		out = append(out, Annotation{Label: "ALLOC", Unit: "B/s", Human: HumanThroughput(bOp.Value * 1e9 / nsOp.Value)})
+	}
+	return out
+}
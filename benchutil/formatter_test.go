@@ -0,0 +1,59 @@
+package benchutil
+
+import (
+	"testing"
+)
+
+func TestFormatter_FormatLine(t *testing.T) {
+	tests := []struct {
+		f     *Formatter
+		input string
+		want  string
+	}{
+		// MB/s from b.SetBytes is kept and shown in human form
+		{
+			NewFormatter(),
+			"BenchmarkHash-8    500    2000 ns/op    1250.75 MB/s",
+			"BenchmarkHash-8\t500\t2000 ns/op\t1250.75 MB/s\tCPU[2µs]\tIO[1.25GB/s]",
+		},
+
+		// MB/s already in the best unit adds no annotation
+		{
+			NewFormatter(),
+			"BenchmarkHash-8    500    2000 ns/op    12.50 MB/s",
+			"BenchmarkHash-8\t500\t2000 ns/op\t12.50 MB/s\tCPU[2µs]",
+		},
+
+		// derived ops/s
+		{
+			NewFormatter().SetOpsPerSec(true),
+			"BenchmarkOps-8    1000    114430 ns/op    808 B/op",
+			"BenchmarkOps-8\t1000\t114430 ns/op\t808 B/op\tCPU[114µs 430ns]\tOPS[8.74K/s]",
+		},
+
+		// derived ops/s and allocation rate
+		{
+			NewFormatter().SetOpsPerSec(true).SetAllocRate(true),
+			"BenchmarkRate-8    1000    1000 ns/op    2048 B/op    1 allocs/op",
+			"BenchmarkRate-8\t1000\t1000 ns/op\t2048 B/op\t1 allocs/op\tCPU[1µs]\tMEM[2KiB]\tOPS[1M/s]\tALLOC[2.05GB/s]",
+		},
+
+		// derived values need ns/op
+		{
+			NewFormatter().SetOpsPerSec(true).SetAllocRate(true),
+			"BenchmarkNoTime-8    1000    2048 B/op",
+			"BenchmarkNoTime-8\t1000\t2048 B/op\tMEM[2KiB]",
+		},
+	}
+
+	for _, tt := range tests {
+		bl, ok := ParseLine(tt.input)
+		if !ok {
+			t.Fatalf("ParseLine(%q) failed", tt.input)
+		}
+		got := tt.f.FormatLine(bl)
+		if got != tt.want {
+			t.Errorf("FormatLine(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
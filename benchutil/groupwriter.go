@@ -0,0 +1,65 @@
+package benchutil
+
+// groupWriter buffers consecutive benchmark records and passes them on to another RecordWriter
+// one group after the other.
+type groupWriter struct {
+	w       RecordWriter
+	groupBy func(Record) string
+	block   []Record
+}
+
+// NewGroupWriter returns a RecordWriter that sets the GroupKey of every benchmark record with groupBy,
+// for example GroupBySubBenchmark or GroupByDimension, and writes the records to w so that every group is contiguous.
+// Groups keep the order of their first benchmark, and benchmarks keep their order within a group.
+// Records are only reordered within a block of consecutive benchmark lines: a block ends at the next log record,
+// which is written in place, or when the package of the benchmarks changes.
+// Blocks are written when they end and on Flush, which also flushes w.
+func NewGroupWriter(w RecordWriter, groupBy func(Record) string) RecordWriter {
+	return &groupWriter{w: w, groupBy: groupBy}
+}
+
+func (g *groupWriter) Write(r Record) error {
+	if r.Type == RecordBenchmark {
+		if len(g.block) > 0 && recordPkg(g.block[0]) != recordPkg(r) {
+			if err := g.writeBlock(); err != nil {
+				return err
+			}
+		}
+		r.GroupKey = g.groupBy(r)
+		g.block = append(g.block, r)
+		return nil
+	}
+	if err := g.writeBlock(); err != nil {
+		return err
+	}
+	return g.w.Write(r)
+}
+
+func (g *groupWriter) Flush() error {
+	if err := g.writeBlock(); err != nil {
+		return err
+	}
+	return g.w.Flush()
+}
+
+// writeBlock writes the buffered block to the underlying writer, group by group.
+func (g *groupWriter) writeBlock() error {
+	var order []string
+	groups := map[string][]Record{}
+	for _, r := range g.block {
+		if _, ok := groups[r.GroupKey]; !ok {
+			order = append(order, r.GroupKey)
+		}
+		groups[r.GroupKey] = append(groups[r.GroupKey], r)
+	}
+	g.block = nil
+
+	for _, key := range order {
+		for _, r := range groups[key] {
+			if err := g.w.Write(r); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package benchutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGroupWriter(t *testing.T) {
+	f := NewFormatter()
+	var buf bytes.Buffer
+	w := NewGroupWriter(NewTextWriter(&buf, f), GroupByDimension("codec"))
+	for _, l := range []string{
+		"pkg: example",
+		"BenchmarkEnc/size=1/codec=zstd-8   10   100 ns/op",
+		"BenchmarkEnc/size=1/codec=gzip-8   10   200 ns/op",
+		"BenchmarkEnc/size=2/codec=zstd-8   10   300 ns/op",
+		"PASS",
+		"BenchmarkEnc/size=3/codec=zstd-8   10   400 ns/op",
+	} {
+		if err := w.Write(f.Record(l)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `pkg: example
+group: codec=zstd
+BenchmarkEnc/size=1/codec=zstd-8	10	100 ns/op
+BenchmarkEnc/size=2/codec=zstd-8	10	300 ns/op
+group: codec=gzip
+BenchmarkEnc/size=1/codec=gzip-8	10	200 ns/op
+PASS
+group: codec=zstd
+BenchmarkEnc/size=3/codec=zstd-8	10	400 ns/op
+`
+	if buf.String() != want {
+		t.Errorf("grouped output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestGroupWriterCSV(t *testing.T) {
+	f := NewFormatter()
+	var buf bytes.Buffer
+	w := NewGroupWriter(NewCSVWriter(&buf, ','), GroupBySubBenchmark)
+	for _, l := range []string{
+		"BenchmarkA/x-8   10   100 ns/op",
+		"BenchmarkB/x-8   10   200 ns/op",
+		"BenchmarkA/y-8   10   300 ns/op",
+	} {
+		if err := w.Write(f.Record(l)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "group,name,") {
+		t.Fatalf("CSV output:\n%s", buf.String())
+	}
+	for i, prefix := range []string{"BenchmarkA,BenchmarkA/x-8,", "BenchmarkA,BenchmarkA/y-8,", "BenchmarkB,BenchmarkB/x-8,"} {
+		if !strings.HasPrefix(lines[i+1], prefix) {
+			t.Errorf("row %d = %q, want prefix %q", i+1, lines[i+1], prefix)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package benchutil
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// HistoryFile is the name of the history file inside the store directory.
+const HistoryFile = "history.jsonl"
+
+// HistoryEntry is one recorded benchmark run.
+type HistoryEntry struct {
+	// Commit is the git commit hash the run was recorded at
+	Commit string `json:"commit"`
+	// Branch is the git branch the run was recorded on
+	Branch string `json:"branch,omitempty"`
+	// Time is when the run was recorded
+	Time time.Time `json:"time"`
+	// Env is the environment header of the first benchmark of the run
+	Env *Env `json:"env,omitempty"`
+	// Benchmarks are the parsed benchmark lines of the run
+	Benchmarks []BenchLine `json:"benchmarks"`
+}
+
+// HistoryStore is a local, append-only benchmark history kept as one JSON entry per line in a single file.
+type HistoryStore struct {
+	// path is the location of the history file
+	path string
+}
+
+// NewHistoryStore creates a history store kept in the HistoryFile inside dir, e.g. ".testmark".
+// The directory and file are created on the first Append.
+func NewHistoryStore(dir string) *HistoryStore {
+	return &HistoryStore{path: filepath.Join(dir, HistoryFile)}
+}
+
+// Append adds an entry to the end of the history.
+func (s *HistoryStore) Append(e HistoryEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Entries returns every entry in the order it was recorded.
+// A missing history file is an empty history.
+func (s *HistoryStore) Entries() ([]HistoryEntry, error) {
+	f, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []HistoryEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", s.path, n, err)
+		}
+		out = append(out, e)
+	}
+	return out, scanner.Err()
+}
+
+// HistoryPoint holds the values of one benchmark in one recorded run.
+type HistoryPoint struct {
+	// Commit is the git commit hash the run was recorded at
+	Commit string
+	// Branch is the git branch the run was recorded on
+	Branch string
+	// Time is when the run was recorded
+	Time time.Time
+	// Procs is the GOMAXPROCS the samples ran with, 1 if their name has no suffix
+	Procs int
+	// Samples are the matching benchmark lines of the run, several if it used -count=N
+	Samples SampleSet
+}
+
+// BenchHistory returns the values of the named benchmark across the entries, oldest first.
+// The name matches either the full benchmark name or the name without its GOMAXPROCS suffix,
+// so "BenchmarkSample/Count10" matches "BenchmarkSample/Count10-8".
+// Runs are never pooled across GOMAXPROCS values: an entry recorded with several -cpu values
+// yields one point per value, in increasing order.
+// Entries that do not contain the benchmark are skipped.
+func BenchHistory(entries []HistoryEntry, name string) []HistoryPoint {
+	var out []HistoryPoint
+	for _, e := range entries {
+		var procs []int
+		samples := map[int]*SampleSet{}
+		for _, l := range e.Benchmarks {
+			if l.Name != name && l.Path() != name {
+				continue
+			}
+			n := l.Procs
+			if n == 0 {
+				n = 1
+			}
+			set, ok := samples[n]
+			if !ok {
+				set = &SampleSet{Name: l.Name}
+				samples[n] = set
+				procs = append(procs, n)
+			}
+			set.Lines = append(set.Lines, l)
+		}
+		sort.Ints(procs)
+		for _, n := range procs {
+			out = append(out, HistoryPoint{Commit: e.Commit, Branch: e.Branch, Time: e.Time, Procs: n, Samples: *samples[n]})
+		}
+	}
+	return out
+}
@@ -0,0 +1,84 @@
+package benchutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHistoryStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), ".testmark")
+	s := NewHistoryStore(dir)
+
+	entries, err := s.Entries()
+	if err != nil || entries != nil {
+		t.Fatalf("Entries() on a missing store = %v, %v, want nil, nil", entries, err)
+	}
+
+	t0 := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	runs := []HistoryEntry{
+		{
+			Commit:     "aaaaaaa",
+			Branch:     "main",
+			Time:       t0,
+			Env:        &Env{GOOS: "linux"},
+			Benchmarks: mustParseLines(t, "BenchmarkA-8   100   1000 ns/op", "BenchmarkB-8   100   50 ns/op"),
+		},
+		{
+			Commit:     "bbbbbbb",
+			Branch:     "main",
+			Time:       t0.Add(time.Hour),
+			Benchmarks: mustParseLines(t, "BenchmarkB-8   100   60 ns/op"),
+		},
+		{
+			Commit:     "ccccccc",
+			Branch:     "feature",
+			Time:       t0.Add(2 * time.Hour),
+			Benchmarks: mustParseLines(t, "BenchmarkA-8   100   900 ns/op", "BenchmarkA-8   100   1100 ns/op"),
+		},
+	}
+	for _, e := range runs {
+		if err := s.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err = s.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || entries[0].Env.GOOS != "linux" || !entries[2].Time.Equal(t0.Add(2*time.Hour)) {
+		t.Fatalf("Entries() = %+v", entries)
+	}
+
+	points := BenchHistory(entries, "BenchmarkA")
+	if len(points) != 2 || points[0].Commit != "aaaaaaa" || points[1].Branch != "feature" {
+		t.Fatalf("BenchHistory(BenchmarkA) = %+v", points)
+	}
+	if s, _ := points[1].Samples.Summary("ns/op"); s.N != 2 || s.Mean != 1000 {
+		t.Errorf("second point summary = %+v, want 2 samples with mean 1000", s)
+	}
+	if points := BenchHistory(entries, "BenchmarkB-8"); len(points) != 2 {
+		t.Errorf("BenchHistory(BenchmarkB-8) returned %d points, want 2", len(points))
+	}
+
+	cpus := []HistoryEntry{{
+		Commit:     "ddddddd",
+		Benchmarks: mustParseLines(t, "BenchmarkC-8   100   100 ns/op", "BenchmarkC   100   800 ns/op", "BenchmarkC-8   100   120 ns/op"),
+	}}
+	points = BenchHistory(cpus, "BenchmarkC")
+	if len(points) != 2 || points[0].Procs != 1 || points[1].Procs != 8 {
+		t.Fatalf("BenchHistory(BenchmarkC) = %+v, want one point per GOMAXPROCS", points)
+	}
+	if s, _ := points[1].Samples.Summary("ns/op"); s.N != 2 || s.Mean != 110 {
+		t.Errorf("procs=8 summary = %+v, want 2 samples with mean 110", s)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, HistoryFile), []byte("{not json\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Entries(); err == nil {
+		t.Errorf("expected an error for a corrupt history file")
+	}
+}
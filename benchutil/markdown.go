@@ -0,0 +1,161 @@
+package benchutil
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// markdownWriter writes benchmark records as GitHub-flavored markdown tables.
+type markdownWriter struct {
+	w       io.Writer
+	groupBy func(Record) string
+	records []Record
+}
+
+// NewMarkdownWriter returns a RecordWriter that renders benchmark records as a GitHub-flavored markdown table
+// with aligned columns and the CPU and MEM human strings. Log records are skipped.
+// If groupBy is not nil, benchmarks are split into one table per group key under a "###" heading,
+// for example with GroupBySubBenchmark or GroupByDimension. When the group key is a prefix of
+// the benchmark name, the benchmark column only shows the rest of the name.
+// Records are buffered and written on Flush.
+func NewMarkdownWriter(w io.Writer, groupBy func(Record) string) RecordWriter {
+	return &markdownWriter{w: w, groupBy: groupBy}
+}
+
+func (m *markdownWriter) Write(r Record) error {
+	if r.Type == RecordBenchmark {
+		m.records = append(m.records, r)
+	}
+	return nil
+}
+
+func (m *markdownWriter) Flush() error {
+	if m.groupBy == nil {
+		return m.writeTable(m.records, "")
+	}
+
+	var order []string
+	groups := map[string][]Record{}
+	for _, r := range m.records {
+		g := m.groupBy(r)
+		if _, ok := groups[g]; !ok {
+			order = append(order, g)
+		}
+		groups[g] = append(groups[g], r)
+	}
+	for i, g := range order {
+		if i > 0 {
+			if _, err := fmt.Fprintln(m.w); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(m.w, "### %s\n\n", g); err != nil {
+			return err
+		}
+		if err := m.writeTable(groups[g], g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTable renders a single markdown table. If group is a "/"-terminated prefix of a benchmark name,
+// the benchmark column shows the rest of the name instead of the full name.
+func (m *markdownWriter) writeTable(records []Record, group string) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	custom := customUnits(records)
+	header := []string{"Benchmark", "Iterations", "ns/op", "CPU", "B/op", "MEM", "allocs/op"}
+	header = append(header, custom...)
+
+	rows := [][]string{header}
+	for _, r := range records {
+		name := r.Name
+		if group != "" && strings.HasPrefix(name, group+"/") {
+			name = strings.TrimPrefix(name, group+"/")
+		}
+		row := []string{
+			markdownEscape(name),
+			strconv.FormatInt(r.Iterations, 10),
+			metricRaw(r, "ns/op"),
+			r.CPU,
+			metricRaw(r, "B/op"),
+			r.MEM,
+			metricRaw(r, "allocs/op"),
+		}
+		for _, u := range custom {
+			row = append(row, metricRaw(r, u))
+		}
+		rows = append(rows, row)
+	}
+
+	widths := make([]int, len(header))
+	for _, row := range rows {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	for i, row := range rows {
+		if _, err := fmt.Fprintln(m.w, markdownRow(row, widths, i > 0)); err != nil {
+			return err
+		}
+		if i == 0 {
+			if _, err := fmt.Fprintln(m.w, markdownSeparator(widths)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// markdownRow renders one table row with every cell padded to its column width.
+// Data cells other than the benchmark name are right-aligned.
+func markdownRow(cells []string, widths []int, data bool) string {
+	var sb strings.Builder
+	sb.WriteString("|")
+	for i, cell := range cells {
+		pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+		if data && i > 0 {
+			sb.WriteString(" " + pad + cell + " |")
+		} else {
+			sb.WriteString(" " + cell + pad + " |")
+		}
+	}
+	return sb.String()
+}
+
+// markdownSeparator renders the header separator row, left-aligning the benchmark column
+// and right-aligning every value column.
+func markdownSeparator(widths []int) string {
+	var sb strings.Builder
+	sb.WriteString("|")
+	for i, w := range widths {
+		if i == 0 {
+			sb.WriteString(" " + strings.Repeat("-", w) + " |")
+		} else {
+			sb.WriteString(" " + strings.Repeat("-", w-1) + ": |")
+		}
+	}
+	return sb.String()
+}
+
+// markdownEscape escapes characters that would break a markdown table cell.
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
+
+// metricRaw returns the raw value of the metric with the given unit, or an empty string if it is missing.
+func metricRaw(r Record, unit string) string {
+	if m, ok := r.Metric(unit); ok {
+		return m.Raw
+	}
+	return ""
+}
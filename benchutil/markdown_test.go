@@ -0,0 +1,54 @@
+package benchutil
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMarkdownWriter(t *testing.T) {
+	f := NewFormatter()
+	lines := []string{
+		"goos: linux",
+		"BenchmarkSample/Count10-8      100000   114430 ns/op   808 B/op    18 allocs/op",
+		"BenchmarkSample/Count1000-8    100000   3651349 ns/op  1128 B/op   18 allocs/op",
+		"BenchmarkPipe|Name-8           10       5 ns/op",
+		"PASS",
+	}
+
+	var buf bytes.Buffer
+	w := NewMarkdownWriter(&buf, nil)
+	for _, l := range lines {
+		_ = w.Write(f.Record(l))
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	want := `| Benchmark                   | Iterations | ns/op   | CPU             | B/op | MEM       | allocs/op |
+| --------------------------- | ---------: | ------: | --------------: | ---: | --------: | --------: |
+| BenchmarkSample/Count10-8   |     100000 |  114430 |     114µs 430ns |  808 |      808B |        18 |
+| BenchmarkSample/Count1000-8 |     100000 | 3651349 | 3ms 651µs 349ns | 1128 | 1KiB 104B |        18 |
+| BenchmarkPipe\|Name-8       |         10 |       5 |             5ns |      |           |           |
+`
+	if buf.String() != want {
+		t.Errorf("markdown output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	w = NewMarkdownWriter(&buf, GroupBySubBenchmark)
+	for _, l := range lines[:3] {
+		_ = w.Write(f.Record(l))
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	want = `### BenchmarkSample
+
+| Benchmark   | Iterations | ns/op   | CPU             | B/op | MEM       | allocs/op |
+| ----------- | ---------: | ------: | --------------: | ---: | --------: | --------: |
+| Count10-8   |     100000 |  114430 |     114µs 430ns |  808 |      808B |        18 |
+| Count1000-8 |     100000 | 3651349 | 3ms 651µs 349ns | 1128 | 1KiB 104B |        18 |
+`
+	if buf.String() != want {
+		t.Errorf("grouped markdown output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,138 @@
+package benchutil
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Metric is a single "value unit" pair reported on a benchmark line,
+// such as "3651349 ns/op" or "12.50 MB/s".
+type Metric struct {
+	// Value is the numeric value of the metric
+	Value float64 `json:"value"`
+	// Raw is the value exactly as it appeared in the input, e.g. "1024.0"
+	Raw string `json:"raw"`
+	// Unit is the unit of the metric, e.g. "ns/op", "B/op" or a custom unit from b.ReportMetric
+	Unit string `json:"unit"`
+}
+
+// BenchLine is the structured form of a single Go benchmark output line.
+type BenchLine struct {
+	// Name is the full benchmark label as printed, e.g. "BenchmarkSample/Count10-8"
+	Name string `json:"name"`
+	// Base is the top-level benchmark name, e.g. "BenchmarkSample"
+	Base string `json:"base"`
+	// Sub holds the sub-benchmark path segments, e.g. ["Count10"]
+	Sub []string `json:"sub,omitempty"`
+	// Procs is the GOMAXPROCS suffix of the name, or 0 if the name has none
+	Procs int `json:"procs,omitempty"`
+	// Iterations is the number of iterations the benchmark ran (b.N)
+	Iterations int64 `json:"iterations"`
+	// Metrics holds every "value unit" pair in the order they appeared
+	Metrics []Metric `json:"metrics"`
+}
+
+// ParseLine parses a standard Go benchmark output line into a BenchLine.
+// A benchmark line consists of a name starting with "Benchmark", an iteration count and at least one "value unit" pair.
+// Parsing of metrics stops at the first field that is not a number, so trailing garbage is ignored.
+// The second return value is false if the line is not a benchmark line.
+func ParseLine(line string) (BenchLine, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || !strings.HasPrefix(fields[0], "Benchmark") {
+		return BenchLine{}, false
+	}
+
+	iterations, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return BenchLine{}, false
+	}
+
+	bl := BenchLine{
+		Name:       fields[0],
+		Iterations: iterations,
+	}
+	bl.Base, bl.Sub, bl.Procs = splitName(fields[0])
+
+	for i := 2; i+1 < len(fields); i += 2 {
+		v, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			break
+		}
+		bl.Metrics = append(bl.Metrics, Metric{Value: v, Raw: fields[i], Unit: fields[i+1]})
+	}
+	if len(bl.Metrics) == 0 {
+		return BenchLine{}, false
+	}
+
+	return bl, true
+}
+
+// Metric returns the first metric with the given unit.
+// The second return value is false if the line has no such metric.
+func (l BenchLine) Metric(unit string) (Metric, bool) {
+	for _, m := range l.Metrics {
+		if m.Unit == unit {
+			return m, true
+		}
+	}
+	return Metric{}, false
+}
+
+// Path returns the benchmark name without the GOMAXPROCS suffix,
+// e.g. "BenchmarkSample/Count10" for "BenchmarkSample/Count10-8".
+func (l BenchLine) Path() string {
+	return strings.Join(append([]string{l.Base}, l.Sub...), "/")
+}
+
+// Segments returns the name hierarchy without the GOMAXPROCS suffix: the base name followed by
+// every sub-benchmark segment, e.g. ["BenchmarkEncode", "size=1024", "codec=zstd"].
+func (l BenchLine) Segments() []string {
+	return append([]string{l.Base}, l.Sub...)
+}
+
+// Group returns the name of the group the benchmark belongs to: the base name followed by
+// every sub-benchmark segment except the last. "BenchmarkSample/Count10-8" is in group "BenchmarkSample".
+// A benchmark without sub-benchmarks is its own group.
+func (l BenchLine) Group() string {
+	if len(l.Sub) == 0 {
+		return l.Base
+	}
+	return strings.Join(append([]string{l.Base}, l.Sub[:len(l.Sub)-1]...), "/")
+}
+
+// Leaf returns the last segment of the benchmark name without the GOMAXPROCS suffix,
+// e.g. "Count10" for "BenchmarkSample/Count10-8".
+func (l BenchLine) Leaf() string {
+	if len(l.Sub) == 0 {
+		return l.Base
+	}
+	return l.Sub[len(l.Sub)-1]
+}
+
+// splitName splits a benchmark label into its base name, sub-benchmark segments
+// and GOMAXPROCS suffix. Go omits the suffix when GOMAXPROCS is 1, in which case procs is 0.
+func splitName(name string) (base string, sub []string, procs int) {
+	if i := strings.LastIndexByte(name, '-'); i > 0 && i < len(name)-1 {
+		if n, err := strconv.Atoi(name[i+1:]); err == nil && n > 0 && isDigits(name[i+1:]) {
+			procs = n
+			name = name[:i]
+		}
+	}
+
+	parts := strings.Split(name, "/")
+	base = parts[0]
+	if len(parts) > 1 {
+		sub = parts[1:]
+	}
+	return base, sub, procs
+}
+
+// isDigits reports whether s consists only of ASCII digits.
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
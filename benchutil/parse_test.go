@@ -0,0 +1,108 @@
+package benchutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLine(t *testing.T) {
+	tests := []struct {
+		input string
+		want  BenchLine
+		ok    bool
+	}{
+		{
+			"BenchmarkSample/Count1000-8     100000   248419 ns/op    1048 B/op       18 allocs/op",
+			BenchLine{
+				Name:       "BenchmarkSample/Count1000-8",
+				Base:       "BenchmarkSample",
+				Sub:        []string{"Count1000"},
+				Procs:      8,
+				Iterations: 100000,
+				Metrics: []Metric{
+					{Value: 248419, Raw: "248419", Unit: "ns/op"},
+					{Value: 1048, Raw: "1048", Unit: "B/op"},
+					{Value: 18, Raw: "18", Unit: "allocs/op"},
+				},
+			},
+			true,
+		},
+		{
+			"BenchmarkEncode/size=1024/codec=zstd   500   12.50 MB/s   3.5 p99-ns",
+			BenchLine{
+				Name:       "BenchmarkEncode/size=1024/codec=zstd",
+				Base:       "BenchmarkEncode",
+				Sub:        []string{"size=1024", "codec=zstd"},
+				Iterations: 500,
+				Metrics: []Metric{
+					{Value: 12.5, Raw: "12.50", Unit: "MB/s"},
+					{Value: 3.5, Raw: "3.5", Unit: "p99-ns"},
+				},
+			},
+			true,
+		},
+		{
+			"BenchmarkGarbage-8 100  500 ns/op  unexpected extra",
+			BenchLine{
+				Name:       "BenchmarkGarbage-8",
+				Base:       "BenchmarkGarbage",
+				Procs:      8,
+				Iterations: 100,
+				Metrics:    []Metric{{Value: 500, Raw: "500", Unit: "ns/op"}},
+			},
+			true,
+		},
+		{"Some unrelated log output", BenchLine{}, false},
+		{"ok  	github.com/rah-0/testmark/benchutil	4.633s", BenchLine{}, false},
+		{"BenchmarkNoMetrics-8   100   unexpected extra", BenchLine{}, false},
+		{"goos: linux", BenchLine{}, false},
+		{"    foo_test.go:12: 5 10 apples", BenchLine{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseLine(tt.input)
+		if ok != tt.ok {
+			t.Errorf("ParseLine(%q) ok = %v, want %v", tt.input, ok, tt.ok)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseLine(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestBenchLine_Metric(t *testing.T) {
+	bl, _ := ParseLine("BenchmarkX-8   10   5000 ns/op   1024 B/op")
+	if m, ok := bl.Metric("B/op"); !ok || m.Value != 1024 {
+		t.Errorf("Metric(B/op) = %+v, %v, want 1024, true", m, ok)
+	}
+	if _, ok := bl.Metric("allocs/op"); ok {
+		t.Errorf("Metric(allocs/op) found, want missing")
+	}
+}
+
+func TestBenchLine_Names(t *testing.T) {
+	tests := []struct {
+		input string
+		path  string
+		group string
+		leaf  string
+	}{
+		{"BenchmarkSample/Count10-8   1   1 ns/op", "BenchmarkSample/Count10", "BenchmarkSample", "Count10"},
+		{"BenchmarkEncode/size=1024/codec=zstd-8   1   1 ns/op", "BenchmarkEncode/size=1024/codec=zstd", "BenchmarkEncode/size=1024", "codec=zstd"},
+		{"BenchmarkPlain-8   1   1 ns/op", "BenchmarkPlain", "BenchmarkPlain", "BenchmarkPlain"},
+	}
+
+	for _, tt := range tests {
+		bl, _ := ParseLine(tt.input)
+		if got := bl.Path(); got != tt.path {
+			t.Errorf("Path(%q) = %q, want %q", tt.input, got, tt.path)
+		}
+		if got := bl.Group(); got != tt.group {
+			t.Errorf("Group(%q) = %q, want %q", tt.input, got, tt.group)
+		}
+		if got := bl.Leaf(); got != tt.leaf {
+			t.Errorf("Leaf(%q) = %q, want %q", tt.input, got, tt.leaf)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package benchutil
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+// PivotTable is a two-dimensional view of one metric, with the values of one name dimension
+// as rows and the values of another as columns.
+type PivotTable struct {
+	// RowKey is the dimension whose values label the rows
+	RowKey string
+	// ColKey is the dimension whose values label the columns
+	ColKey string
+	// Unit is the metric unit of the cells
+	Unit string
+	// Rows are the row dimension values, numeric values first in ascending order
+	Rows []Dimension
+	// Cols are the column dimension values, numeric values first in ascending order
+	Cols []Dimension
+	// Cells holds the statistics of every row and column, indexed as Cells[row][col].
+	// A cell without samples has N == 0.
+	Cells [][]Summary
+}
+
+// Pivot builds a pivot table of the metric with the given unit. Every benchmark line that has both
+// the rowKey and colKey dimensions and the metric contributes a sample to its cell, so repeated runs
+// are summarized. Lines missing any of them are ignored.
+func Pivot(lines []BenchLine, rowKey, colKey, unit string) PivotTable {
+	var used []BenchLine
+	for _, l := range lines {
+		_, okRow := l.Dimension(rowKey)
+		_, okCol := l.Dimension(colKey)
+		_, okMetric := l.Metric(unit)
+		if okRow && okCol && okMetric {
+			used = append(used, l)
+		}
+	}
+
+	p := PivotTable{
+		RowKey: rowKey,
+		ColKey: colKey,
+		Unit:   unit,
+		Rows:   DimensionValues(used, rowKey),
+		Cols:   DimensionValues(used, colKey),
+	}
+	rowIndex := dimensionIndex(p.Rows)
+	colIndex := dimensionIndex(p.Cols)
+
+	values := make([][][]float64, len(p.Rows))
+	for i := range values {
+		values[i] = make([][]float64, len(p.Cols))
+	}
+	for _, l := range used {
+		r, _ := l.Dimension(rowKey)
+		c, _ := l.Dimension(colKey)
+		m, _ := l.Metric(unit)
+		i, j := rowIndex[r.Value], colIndex[c.Value]
+		values[i][j] = append(values[i][j], m.Value)
+	}
+
+	p.Cells = make([][]Summary, len(p.Rows))
+	for i := range values {
+		p.Cells[i] = make([]Summary, len(p.Cols))
+		for j := range values[i] {
+			p.Cells[i][j] = Summarize(values[i][j])
+		}
+	}
+	return p
+}
+
+// dimensionIndex maps every dimension value to its position.
+func dimensionIndex(dims []Dimension) map[string]int {
+	out := make(map[string]int, len(dims))
+	for i, d := range dims {
+		out[d.Value] = i
+	}
+	return out
+}
+
+// Table returns the pivot table as rows of cells, starting with a header row.
+// The top-left cell is "rowKey \ colKey". Cells hold the mean of their samples formatted with HumanValue,
+// or with up to two decimals if raw is true. Cells without samples are empty.
+func (p PivotTable) Table(raw bool) [][]string {
+	header := []string{p.RowKey + ` \ ` + p.ColKey}
+	for _, c := range p.Cols {
+		header = append(header, c.Value)
+	}
+
+	out := [][]string{header}
+	for i, r := range p.Rows {
+		row := []string{r.Value}
+		for _, s := range p.Cells[i] {
+			switch {
+			case s.N == 0:
+				row = append(row, "")
+			case raw:
+				row = append(row, formatNumber(s.Mean))
+			default:
+				row = append(row, HumanValue(p.Unit, s.Mean))
+			}
+		}
+		out = append(out, row)
+	}
+	return out
+}
+
+// WriteText writes the pivot table as aligned plain text columns. Cells without samples show "-".
+func (p PivotTable) WriteText(w io.Writer, raw bool) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, row := range p.Table(raw) {
+		for i, cell := range row {
+			if cell == "" {
+				row[i] = "-"
+			}
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// WriteMarkdown writes the pivot table as a GitHub-flavored markdown table
+// with the row labels left-aligned and the cells right-aligned.
+func (p PivotTable) WriteMarkdown(w io.Writer, raw bool) error {
+	rows := p.Table(raw)
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			row[i] = markdownEscape(cell)
+			if n := utf8.RuneCountInString(row[i]); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	// the separator needs at least "-:" in right-aligned columns
+	for i := range widths {
+		if widths[i] < 2 {
+			widths[i] = 2
+		}
+	}
+
+	for i, row := range rows {
+		if _, err := fmt.Fprintln(w, markdownRow(row, widths, i > 0)); err != nil {
+			return err
+		}
+		if i == 0 {
+			if _, err := fmt.Fprintln(w, markdownSeparator(widths)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes the pivot table as delimiter-separated values. Use ',' for CSV and '\t' for TSV.
+func (p PivotTable) WriteCSV(w io.Writer, comma rune, raw bool) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	if err := cw.WriteAll(p.Table(raw)); err != nil {
+		return err
+	}
+	return cw.Error()
+}
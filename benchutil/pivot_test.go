@@ -0,0 +1,74 @@
+package benchutil
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPivot(t *testing.T) {
+	lines := mustParseLines(t,
+		"BenchmarkEncode/size=1024/codec=zstd-8   1000   3500000 ns/op",
+		"BenchmarkEncode/size=64/codec=zstd-8   1000   1500 ns/op",
+		"BenchmarkEncode/size=64/codec=gzip-8   1000   2500 ns/op",
+		"BenchmarkEncode/size=1024/codec=zstd-8   1000   3700000 ns/op",
+		"BenchmarkEncode/size=64-8   1000   100 ns/op",
+	)
+
+	p := Pivot(lines, "size", "codec", "ns/op")
+	if len(p.Rows) != 2 || p.Rows[0].Value != "64" || p.Rows[1].Value != "1024" {
+		t.Fatalf("Rows = %+v, want 64 and 1024", p.Rows)
+	}
+	if len(p.Cols) != 2 || p.Cols[0].Value != "zstd" || p.Cols[1].Value != "gzip" {
+		t.Fatalf("Cols = %+v, want zstd and gzip", p.Cols)
+	}
+	if c := p.Cells[1][0]; c.N != 2 || c.Mean != 3600000 {
+		t.Errorf("Cells[1][0] = %+v, want 2 samples with mean 3600000", c)
+	}
+	if c := p.Cells[1][1]; c.N != 0 {
+		t.Errorf("Cells[1][1] = %+v, want no samples", c)
+	}
+
+	var buf bytes.Buffer
+	if err := p.WriteText(&buf, false); err != nil {
+		t.Fatal(err)
+	}
+	want := `size \ codec  zstd       gzip
+64            1µs 500ns  2µs 500ns
+1024          3ms 600µs  -
+`
+	if buf.String() != want {
+		t.Errorf("text output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := p.WriteMarkdown(&buf, false); err != nil {
+		t.Fatal(err)
+	}
+	want = `| size \ codec | zstd      | gzip      |
+| ------------ | --------: | --------: |
+| 64           | 1µs 500ns | 2µs 500ns |
+| 1024         | 3ms 600µs |           |
+`
+	if buf.String() != want {
+		t.Errorf("markdown output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := p.WriteCSV(&buf, ',', true); err != nil {
+		t.Fatal(err)
+	}
+	want = `size \ codec,zstd,gzip
+64,1500,2500
+1024,3600000,
+`
+	if buf.String() != want {
+		t.Errorf("raw CSV output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestPivotMissingMetric(t *testing.T) {
+	lines := mustParseLines(t, "BenchmarkEncode/size=64/codec=zstd-8   1000   1500 ns/op")
+	if p := Pivot(lines, "size", "codec", "B/op"); len(p.Rows) != 0 || len(p.Cols) != 0 {
+		t.Errorf("Pivot() on a missing metric = %+v, want an empty table", p)
+	}
+}
@@ -0,0 +1,186 @@
+package benchutil
+
+import (
+	"html/template"
+	"io"
+	"strings"
+)
+
+// reportData is the data rendered by the HTML report template.
+type reportData struct {
+	Env    [][2]string
+	Groups []reportGroup
+}
+
+// reportGroup is a table and its charts for one benchmark group.
+type reportGroup struct {
+	Name    string
+	Pkg     string
+	Rows    []reportRow
+	NsChart template.HTML
+	BChart  template.HTML
+}
+
+// reportRow is one benchmark row of a report table.
+// Metrics missing from the benchmark line are nil.
+type reportRow struct {
+	Name       string
+	Iterations int64
+	NsOp       *Metric
+	BOp        *Metric
+	Allocs     *Metric
+	CPU        string
+	MEM        string
+}
+
+// WriteHTMLReport writes a self-contained HTML report for the given records.
+// The report shows the goos/goarch/pkg/cpu header of the first benchmark, one sortable table per
+// package and benchmark group, and inline SVG bar charts of ns/op and B/op per group.
+// Records should come from a Stream so that benchmarks carry their environment.
+// It uses no external resources.
+func WriteHTMLReport(w io.Writer, records []Record) error {
+	data := reportData{}
+	if env := FirstEnv(records); env != nil {
+		for _, kv := range [][2]string{{"goos", env.GOOS}, {"goarch", env.GOARCH}, {"pkg", env.Pkg}, {"cpu", env.CPU}} {
+			if kv[1] != "" {
+				data.Env = append(data.Env, kv)
+			}
+		}
+	}
+
+	groupIndex := map[string]int{}
+	for _, r := range records {
+		if r.Type == RecordLog {
+			continue
+		}
+
+		pkg := ""
+		if r.Env != nil {
+			pkg = r.Env.Pkg
+		}
+		key := pkg + "\x00" + r.Group()
+		i, ok := groupIndex[key]
+		if !ok {
+			i = len(data.Groups)
+			groupIndex[key] = i
+			data.Groups = append(data.Groups, reportGroup{Name: r.Group(), Pkg: pkg})
+		}
+
+		row := reportRow{Name: r.Name, Iterations: r.Iterations, CPU: r.CPU, MEM: r.MEM}
+		if m, ok := r.Metric("ns/op"); ok {
+			row.NsOp = &m
+		}
+		if m, ok := r.Metric("B/op"); ok {
+			row.BOp = &m
+		}
+		if m, ok := r.Metric("allocs/op"); ok {
+			row.Allocs = &m
+		}
+		data.Groups[i].Rows = append(data.Groups[i].Rows, row)
+	}
+
+	for i := range data.Groups {
+		g := &data.Groups[i]
+		var ns, b []barChartItem
+		for _, row := range g.Rows {
+			label := row.Name
+			if strings.HasPrefix(label, g.Name+"/") {
+				label = strings.TrimPrefix(label, g.Name+"/")
+			}
+			if row.NsOp != nil {
+				ns = append(ns, barChartItem{Label: label, Value: row.NsOp.Value})
+			}
+			if row.BOp != nil {
+				b = append(b, barChartItem{Label: label, Value: row.BOp.Value})
+			}
+		}
+		if len(ns) > 0 {
+			g.NsChart = template.HTML(svgBarChart("ns/op", ns, HumanNsFloat))
+		}
+		if len(b) > 0 {
+			g.BChart = template.HTML(svgBarChart("B/op", b, func(v float64) string { return HumanValue("B/op", v) }))
+		}
+	}
+
+	return reportTemplate.Execute(w, data)
+}
+
+// reportTemplate is the HTML report layout. Styles and the table sorting script are inlined
+// so the report works offline as a single file.
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>testmark report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; }
+th { background: #f0f0f0; cursor: pointer; user-select: none; }
+td.num { text-align: right; font-variant-numeric: tabular-nums; }
+dl.env { display: grid; grid-template-columns: max-content auto; gap: 2px 12px; }
+dl.env dt { font-weight: bold; }
+dl.env dd { margin: 0; }
+p.pkg { color: #666; margin-top: -0.5em; }
+.charts svg { display: block; margin-bottom: 1em; }
+</style>
+</head>
+<body>
+<h1>testmark report</h1>
+{{- if .Env}}
+<dl class="env">
+{{- range .Env}}
+<dt>{{index . 0}}</dt><dd>{{index . 1}}</dd>
+{{- end}}
+</dl>
+{{- end}}
+{{- range .Groups}}
+<h2>{{.Name}}</h2>
+{{- if .Pkg}}
+<p class="pkg">pkg: {{.Pkg}}</p>
+{{- end}}
+<table class="sortable">
+<thead><tr><th>Benchmark</th><th>Iterations</th><th>ns/op</th><th>CPU</th><th>B/op</th><th>MEM</th><th>allocs/op</th></tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr><td>{{.Name}}</td><td class="num" data-sort="{{.Iterations}}">{{.Iterations}}</td>
+{{- if .NsOp}}<td class="num" data-sort="{{.NsOp.Raw}}">{{.NsOp.Raw}}</td><td class="num" data-sort="{{.NsOp.Raw}}">{{.CPU}}</td>{{else}}<td></td><td></td>{{end}}
+{{- if .BOp}}<td class="num" data-sort="{{.BOp.Raw}}">{{.BOp.Raw}}</td><td class="num" data-sort="{{.BOp.Raw}}">{{.MEM}}</td>{{else}}<td></td><td></td>{{end}}
+{{- if .Allocs}}<td class="num" data-sort="{{.Allocs.Raw}}">{{.Allocs.Raw}}</td>{{else}}<td></td>{{end}}</tr>
+{{- end}}
+</tbody>
+</table>
+<div class="charts">
+{{.NsChart}}
+{{.BChart}}
+</div>
+{{- end}}
+<script>
+document.querySelectorAll("table.sortable").forEach(function (table) {
+  table.querySelectorAll("th").forEach(function (th, col) {
+    var asc = true;
+    th.addEventListener("click", function () {
+      var tbody = table.tBodies[0];
+      var rows = Array.prototype.slice.call(tbody.rows);
+      rows.sort(function (a, b) {
+        var x = a.cells[col], y = b.cells[col];
+        var xs = x.getAttribute("data-sort"), ys = y.getAttribute("data-sort");
+        var r;
+        if (xs !== null && ys !== null) {
+          r = parseFloat(xs) - parseFloat(ys);
+        } else if (xs !== null || ys !== null) {
+          return xs === null ? 1 : -1;
+        } else {
+          r = x.textContent.localeCompare(y.textContent);
+        }
+        return asc ? r : -r;
+      });
+      rows.forEach(function (row) { tbody.appendChild(row); });
+      asc = !asc;
+    });
+  });
+});
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,60 @@
+package benchutil
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteHTMLReport(t *testing.T) {
+	s := NewFormatter().NewStream()
+	lines := []string{
+		"goos: linux",
+		"goarch: amd64",
+		"pkg: example.com/sample",
+		"cpu: Test CPU <fast>",
+		"BenchmarkSample/Count10-8      100000   114430 ns/op   808 B/op    18 allocs/op",
+		"BenchmarkSample/Count1000-8    100000   3651349 ns/op  1128 B/op   18 allocs/op",
+		"BenchmarkOther-8               10       5 ns/op",
+		"PASS",
+	}
+	var records []Record
+	for _, l := range lines {
+		records = append(records, s.Record(l))
+	}
+
+	var buf bytes.Buffer
+	if err := WriteHTMLReport(&buf, records); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"<dt>goos</dt><dd>linux</dd>",
+		"<dt>cpu</dt><dd>Test CPU &lt;fast&gt;</dd>",
+		"<h2>BenchmarkSample</h2>",
+		"<h2>BenchmarkOther</h2>",
+		`<p class="pkg">pkg: example.com/sample</p>`,
+		`data-sort="3651349">3ms 651µs 349ns</td>`,
+		">1KiB 104B</td>",
+		"<svg",
+		">Count1000-8</text>",
+		"table.sortable",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("report does not contain %q", want)
+		}
+	}
+	if strings.Contains(out, "<script src") || strings.Contains(out, "<link") {
+		t.Errorf("report must not reference external resources")
+	}
+}
+
+func TestSvgBarChart(t *testing.T) {
+	out := svgBarChart("ns/op", []barChartItem{{"a<b", 50}, {"c", 100}}, func(v float64) string { return HumanNs(int64(v)) })
+	for _, want := range []string{`<svg xmlns="http://www.w3.org/2000/svg"`, ">a&lt;b</text>", `width="360.0"`, `width="180.0"`, ">100ns</text>"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("chart does not contain %q:\n%s", want, out)
+		}
+	}
+}
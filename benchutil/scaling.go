@@ -0,0 +1,140 @@
+package benchutil
+
+import (
+	"math"
+	"sort"
+)
+
+// ScalingPoint is the ns/op of a benchmark at one GOMAXPROCS value.
+type ScalingPoint struct {
+	// Procs is the GOMAXPROCS value, 1 for names without a suffix
+	Procs int
+	// N is the number of samples averaged into NsPerOp
+	N int
+	// NsPerOp is the mean ns/op of the samples
+	NsPerOp float64
+	// Speedup is the ns/op at the lowest measured procs divided by NsPerOp
+	Speedup float64
+	// Efficiency is Speedup divided by the procs ratio to the lowest measured procs, 1 for perfect scaling
+	Efficiency float64
+}
+
+// ScalingAnalysis describes how one benchmark scales with GOMAXPROCS,
+// as measured by running it with go test -cpu=1,2,4,8.
+type ScalingAnalysis struct {
+	// Name is the benchmark name without the GOMAXPROCS suffix
+	Name string
+	// Points are the measurements ordered by procs
+	Points []ScalingPoint
+	// SerialFraction is the Amdahl's law estimate of the share of work that does not parallelize, between 0 and 1
+	SerialFraction float64
+	// MaxSpeedup is the speedup Amdahl's law predicts for unlimited procs, 1/SerialFraction; +Inf if nothing is serial
+	MaxSpeedup float64
+	// R2 is the coefficient of determination of the Amdahl fit
+	R2 float64
+}
+
+// AnalyzeScaling groups benchmark lines by name without the GOMAXPROCS suffix and describes how the ns/op of
+// every benchmark measured with at least two procs values scales. Repeated runs are averaged.
+// Speedup and efficiency are relative to the lowest measured procs. The serial fraction comes from a
+// least-squares fit of Amdahl's law, T(p) = T1 * (s + (1-s)/p), to the mean ns/op of every procs value.
+// Benchmarks are returned in order of first appearance.
+func AnalyzeScaling(lines []BenchLine) []ScalingAnalysis {
+	type sample struct {
+		sum float64
+		n   int
+	}
+	var order []string
+	samples := map[string]map[int]*sample{}
+	for _, l := range lines {
+		m, ok := l.Metric("ns/op")
+		if !ok {
+			continue
+		}
+		name := l.Path()
+		if _, ok := samples[name]; !ok {
+			order = append(order, name)
+			samples[name] = map[int]*sample{}
+		}
+		procs := l.Procs
+		if procs == 0 {
+			procs = 1
+		}
+		s, ok := samples[name][procs]
+		if !ok {
+			s = &sample{}
+			samples[name][procs] = s
+		}
+		s.sum += m.Value
+		s.n++
+	}
+
+	var out []ScalingAnalysis
+	for _, name := range order {
+		if len(samples[name]) < 2 {
+			continue
+		}
+		a := ScalingAnalysis{Name: name}
+		for procs, s := range samples[name] {
+			a.Points = append(a.Points, ScalingPoint{Procs: procs, N: s.n, NsPerOp: s.sum / float64(s.n)})
+		}
+		sort.Slice(a.Points, func(i, j int) bool { return a.Points[i].Procs < a.Points[j].Procs })
+
+		base := a.Points[0]
+		for i := range a.Points {
+			p := &a.Points[i]
+			if p.NsPerOp > 0 {
+				p.Speedup = base.NsPerOp / p.NsPerOp
+			}
+			p.Efficiency = p.Speedup * float64(base.Procs) / float64(p.Procs)
+		}
+		a.SerialFraction, a.R2 = fitAmdahl(a.Points)
+		a.MaxSpeedup = math.Inf(1)
+		if a.SerialFraction > 0 {
+			a.MaxSpeedup = 1 / a.SerialFraction
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// fitAmdahl fits T(p) = a + b/p by least squares, where a = T1*s is the serial and b = T1*(1-s) the
+// parallel part of the work, and returns the serial fraction s = a/(a+b) clamped to [0, 1] and R² of the fit.
+func fitAmdahl(points []ScalingPoint) (serial, r2 float64) {
+	n := float64(len(points))
+	var mx, my float64
+	for _, p := range points {
+		mx += 1 / float64(p.Procs)
+		my += p.NsPerOp
+	}
+	mx /= n
+	my /= n
+
+	var sxx, sxy, syy float64
+	for _, p := range points {
+		dx, dy := 1/float64(p.Procs)-mx, p.NsPerOp-my
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+	b := sxy / sxx
+	a := my - b*mx
+
+	if syy > 0 {
+		var ssRes float64
+		for _, p := range points {
+			r := p.NsPerOp - (a + b/float64(p.Procs))
+			ssRes += r * r
+		}
+		r2 = 1 - ssRes/syy
+	}
+
+	switch {
+	case a <= 0:
+		return 0, r2
+	case b <= 0:
+		return 1, r2
+	default:
+		return a / (a + b), r2
+	}
+}
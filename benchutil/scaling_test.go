@@ -0,0 +1,59 @@
+package benchutil
+
+import (
+	"math"
+	"testing"
+)
+
+func TestAnalyzeScaling(t *testing.T) {
+	// Amdahl's law with T1 = 1000ns and a serial fraction of 10%
+	lines := mustParseLines(t,
+		"BenchmarkCacheGet   1000   1000 ns/op",
+		"BenchmarkCacheGet-2   1000   550 ns/op",
+		"BenchmarkCacheGet-4   1000   325 ns/op",
+		"BenchmarkCacheGet-8   1000   200 ns/op",
+		"BenchmarkCacheGet-8   1000   225 ns/op",
+		"BenchmarkSingle-8   1000   100 ns/op",
+	)
+
+	got := AnalyzeScaling(lines)
+	if len(got) != 1 {
+		t.Fatalf("AnalyzeScaling() returned %d analyses, want 1", len(got))
+	}
+	a := got[0]
+	if a.Name != "BenchmarkCacheGet" || len(a.Points) != 4 {
+		t.Fatalf("analysis = %+v, want BenchmarkCacheGet with 4 points", a)
+	}
+
+	last := a.Points[3]
+	if last.Procs != 8 || last.N != 2 || last.NsPerOp != 212.5 {
+		t.Errorf("Points[3] = %+v, want procs 8 with 2 samples averaging 212.5", last)
+	}
+	if !approxEqual(last.Speedup, 1000/212.5, 1e-9) || !approxEqual(last.Efficiency, 1000/212.5/8, 1e-9) {
+		t.Errorf("Points[3] speedup %v efficiency %v", last.Speedup, last.Efficiency)
+	}
+	if !approxEqual(a.SerialFraction, 0.1, 1e-9) || !approxEqual(a.MaxSpeedup, 10, 1e-6) || !approxEqual(a.R2, 1, 1e-9) {
+		t.Errorf("Amdahl fit = %v serial, %vx max, R² %v, want 0.1, 10x, 1", a.SerialFraction, a.MaxSpeedup, a.R2)
+	}
+}
+
+func TestAnalyzeScalingRelativeToLowestProcs(t *testing.T) {
+	lines := mustParseLines(t,
+		"BenchmarkPerfect-2   1000   500 ns/op",
+		"BenchmarkPerfect-4   1000   250 ns/op",
+		"BenchmarkPerfect-8   1000   125 ns/op",
+	)
+
+	a := AnalyzeScaling(lines)[0]
+	for _, p := range a.Points {
+		if !approxEqual(p.Efficiency, 1, 1e-9) {
+			t.Errorf("procs %d efficiency = %v, want 1", p.Procs, p.Efficiency)
+		}
+	}
+	if a.Points[2].Speedup != 4 {
+		t.Errorf("speedup at 8 procs = %v, want 4 relative to 2 procs", a.Points[2].Speedup)
+	}
+	if a.SerialFraction != 0 || !math.IsInf(a.MaxSpeedup, 1) {
+		t.Errorf("serial fraction = %v, max speedup %v, want 0 and +Inf", a.SerialFraction, a.MaxSpeedup)
+	}
+}
@@ -0,0 +1,148 @@
+package benchutil
+
+import (
+	"math"
+	"sort"
+)
+
+// HypothesisTest computes the two-sided p-value for the null hypothesis that
+// samples a and b come from the same distribution.
+type HypothesisTest func(a, b []float64) float64
+
+// MannWhitneyU performs a two-sided Mann-Whitney U test and returns its p-value.
+// Small samples without ties use the exact distribution of U, larger samples or samples with ties
+// use the normal approximation with tie and continuity correction.
+// It returns 1 if either sample is empty or if every value is identical.
+func MannWhitneyU(a, b []float64) float64 {
+	n1, n2 := len(a), len(b)
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+
+	type ranked struct {
+		v     float64
+		fromA bool
+	}
+	all := make([]ranked, 0, n1+n2)
+	for _, v := range a {
+		all = append(all, ranked{v, true})
+	}
+	for _, v := range b {
+		all = append(all, ranked{v, false})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].v < all[j].v })
+
+	// Assign average ranks to ties and accumulate the tie correction term.
+	var rankSumA, tieTerm float64
+	ties := false
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].v == all[i].v {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].fromA {
+				rankSumA += rank
+			}
+		}
+		if t := float64(j - i); t > 1 {
+			ties = true
+			tieTerm += t*t*t - t
+		}
+		i = j
+	}
+	u := rankSumA - float64(n1*(n1+1))/2
+
+	if !ties && n1+n2 <= 40 {
+		return mannWhitneyExactP(n1, n2, int(u))
+	}
+
+	n := float64(n1 + n2)
+	mean := float64(n1*n2) / 2
+	variance := float64(n1*n2) / 12 * ((n + 1) - tieTerm/(n*(n-1)))
+	if variance <= 0 {
+		return 1
+	}
+	z := (math.Abs(u-mean) - 0.5) / math.Sqrt(variance)
+	if z < 0 {
+		z = 0
+	}
+	return math.Min(1, math.Erfc(z/math.Sqrt2))
+}
+
+// mannWhitneyExactP returns the exact two-sided p-value of observing u for samples of size n1 and n2.
+func mannWhitneyExactP(n1, n2, u int) float64 {
+	// freq[i][j][k] is the number of orderings of i values from a and j values from b with U = k.
+	max := n1 * n2
+	freq := make([][][]float64, n1+1)
+	for i := range freq {
+		freq[i] = make([][]float64, n2+1)
+		for j := range freq[i] {
+			freq[i][j] = make([]float64, max+1)
+			if i == 0 || j == 0 {
+				freq[i][j][0] = 1
+				continue
+			}
+			for k := 0; k <= i*j; k++ {
+				if k >= j {
+					freq[i][j][k] += freq[i-1][j][k-j]
+				}
+				freq[i][j][k] += freq[i][j-1][k]
+			}
+		}
+	}
+
+	var total, lower, upper float64
+	for k, f := range freq[n1][n2] {
+		total += f
+		if k <= u {
+			lower += f
+		}
+		if k >= u {
+			upper += f
+		}
+	}
+	return math.Min(1, 2*math.Min(lower, upper)/total)
+}
+
+// mannWhitneyMinP returns the smallest two-sided p-value the exact Mann-Whitney U test can produce
+// for samples of size n1 and n2, reached when the samples do not overlap: 2 / C(n1+n2, n1).
+func mannWhitneyMinP(n1, n2 int) float64 {
+	orderings := 1.0
+	for i := 1; i <= n1; i++ {
+		orderings = orderings * float64(n2+i) / float64(i)
+	}
+	return math.Min(1, 2/orderings)
+}
+
+// mannWhitneySamples returns the smallest number of samples per side with which the Mann-Whitney U test
+// can reach the significance level alpha, e.g. 4 for 0.05.
+func mannWhitneySamples(alpha float64) int {
+	n := 2
+	for mannWhitneyMinP(n, n) > alpha {
+		n++
+	}
+	return n
+}
+
+// WelchTTest performs a two-sided Welch's t-test, which does not assume equal variances, and returns its p-value.
+// It returns 1 if either sample has fewer than two values.
+func WelchTTest(a, b []float64) float64 {
+	if len(a) < 2 || len(b) < 2 {
+		return 1
+	}
+	sa, sb := Summarize(a), Summarize(b)
+	va := sa.StdDev * sa.StdDev / float64(sa.N)
+	vb := sb.StdDev * sb.StdDev / float64(sb.N)
+	if va+vb == 0 {
+		if sa.Mean == sb.Mean {
+			return 1
+		}
+		return 0
+	}
+
+	t := (sa.Mean - sb.Mean) / math.Sqrt(va+vb)
+	df := (va + vb) * (va + vb) / (va*va/float64(sa.N-1) + vb*vb/float64(sb.N-1))
+	return math.Min(1, 2*(1-studentTCDF(math.Abs(t), df)))
+}
@@ -0,0 +1,82 @@
+package benchutil
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMannWhitneyU(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float64
+		want float64
+		tol  float64
+	}{
+		// exact: complete separation of 5+5 samples, p = 2/252
+		{"separated", []float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10}, 2.0 / 252, 1e-9},
+		// exact: interleaved samples, U = 10, P(U <= 10) = 87/252
+		{"interleaved", []float64{1, 3, 5, 7, 9}, []float64{2, 4, 6, 8, 10}, 2 * 87.0 / 252, 1e-9},
+		// normal approximation with ties: U = 1.5, z = 2.6224
+		{"ties", []float64{1, 2, 2, 3, 3, 3}, []float64{3, 4, 4, 5, 5, 6}, 0.00873, 1e-5},
+		{"identical", []float64{18, 18, 18}, []float64{18, 18, 18}, 1, 0},
+		{"empty", nil, []float64{1}, 1, 0},
+	}
+
+	for _, tt := range tests {
+		if got := MannWhitneyU(tt.a, tt.b); !approxEqual(got, tt.want, tt.tol) {
+			t.Errorf("%s: MannWhitneyU() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWelchTTest(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []float64
+		want float64
+		tol  float64
+	}{
+		// t = -3.3627 with 8.99 degrees of freedom
+		{"different", []float64{10, 12, 9, 11, 13}, []float64{14, 15, 13, 16, 12, 17}, 0.00837, 1e-5},
+		{"same", []float64{1, 2, 3}, []float64{1, 2, 3}, 1, 1e-9},
+		{"no variance equal", []float64{5, 5}, []float64{5, 5}, 1, 0},
+		{"no variance different", []float64{5, 5}, []float64{6, 6}, 0, 0},
+		{"too few", []float64{1}, []float64{2, 3}, 1, 0},
+	}
+
+	for _, tt := range tests {
+		if got := WelchTTest(tt.a, tt.b); !approxEqual(got, tt.want, tt.tol) {
+			t.Errorf("%s: WelchTTest() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMannWhitneyMinP(t *testing.T) {
+	tests := []struct {
+		n1, n2 int
+		want   float64
+	}{
+		{2, 2, 1.0 / 3},
+		{3, 3, 0.1},
+		{4, 4, 2.0 / 70},
+		{3, 5, 2.0 / 56},
+	}
+	sequence := func(n int, from float64) []float64 {
+		var out []float64
+		for i := 0; i < n; i++ {
+			out = append(out, from+float64(i))
+		}
+		return out
+	}
+	for _, tt := range tests {
+		if got := mannWhitneyMinP(tt.n1, tt.n2); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("mannWhitneyMinP(%d, %d) = %v, want %v", tt.n1, tt.n2, got, tt.want)
+		}
+		if p := MannWhitneyU(sequence(tt.n1, 0), sequence(tt.n2, 100)); math.Abs(p-tt.want) > 1e-12 {
+			t.Errorf("MannWhitneyU of disjoint samples of %d and %d = %v, want %v", tt.n1, tt.n2, p, tt.want)
+		}
+	}
+	if got := mannWhitneySamples(0.05); got != 4 {
+		t.Errorf("mannWhitneySamples(0.05) = %d, want 4", got)
+	}
+}
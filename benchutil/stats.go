@@ -0,0 +1,201 @@
+package benchutil
+
+import (
+	"math"
+	"sort"
+)
+
+// Summary holds descriptive statistics of a set of samples.
+type Summary struct {
+	// N is the number of samples
+	N int `json:"n"`
+	// Mean is the arithmetic mean of the samples
+	Mean float64 `json:"mean"`
+	// Median is the middle sample, or the mean of the two middle samples for an even count
+	Median float64 `json:"median"`
+	// Min is the smallest sample
+	Min float64 `json:"min"`
+	// Max is the largest sample
+	Max float64 `json:"max"`
+	// StdDev is the sample standard deviation (n-1 denominator), 0 for a single sample
+	StdDev float64 `json:"stddev"`
+	// CI95 is the half-width of the 95% confidence interval of the mean, based on Student's t-distribution
+	CI95 float64 `json:"ci95"`
+	// CV is the coefficient of variation in percent (StdDev / Mean * 100), 0 if Mean is 0
+	CV float64 `json:"cv"`
+}
+
+// Summarize computes descriptive statistics for the given samples.
+// It returns the zero Summary if values is empty.
+func Summarize(values []float64) Summary {
+	n := len(values)
+	if n == 0 {
+		return Summary{}
+	}
+
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+
+	s := Summary{N: n, Min: sorted[0], Max: sorted[n-1]}
+	for _, v := range sorted {
+		s.Mean += v
+	}
+	s.Mean /= float64(n)
+
+	if n%2 == 1 {
+		s.Median = sorted[n/2]
+	} else {
+		s.Median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+
+	if n > 1 {
+		var ss float64
+		for _, v := range sorted {
+			ss += (v - s.Mean) * (v - s.Mean)
+		}
+		s.StdDev = math.Sqrt(ss / float64(n-1))
+		s.CI95 = tQuantile(0.975, float64(n-1)) * s.StdDev / math.Sqrt(float64(n))
+	}
+	if s.Mean != 0 {
+		s.CV = s.StdDev / math.Abs(s.Mean) * 100
+	}
+	return s
+}
+
+// SampleSet holds the repeated runs of one benchmark, as produced by go test -count=N.
+type SampleSet struct {
+	// Name is the full benchmark name shared by every line
+	Name string
+	// Lines are the benchmark lines in input order
+	Lines []BenchLine
+}
+
+// GroupSamples groups benchmark lines by full name, in order of first appearance.
+func GroupSamples(lines []BenchLine) []SampleSet {
+	var out []SampleSet
+	index := map[string]int{}
+	for _, l := range lines {
+		i, ok := index[l.Name]
+		if !ok {
+			i = len(out)
+			index[l.Name] = i
+			out = append(out, SampleSet{Name: l.Name})
+		}
+		out[i].Lines = append(out[i].Lines, l)
+	}
+	return out
+}
+
+// Units returns the units of every metric found in the set, in order of first appearance.
+func (s SampleSet) Units() []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, l := range s.Lines {
+		for _, m := range l.Metrics {
+			if !seen[m.Unit] {
+				seen[m.Unit] = true
+				out = append(out, m.Unit)
+			}
+		}
+	}
+	return out
+}
+
+// Values returns the value of the metric with the given unit from every line that has it.
+func (s SampleSet) Values(unit string) []float64 {
+	var out []float64
+	for _, l := range s.Lines {
+		if m, ok := l.Metric(unit); ok {
+			out = append(out, m.Value)
+		}
+	}
+	return out
+}
+
+// Summary summarizes the metric with the given unit across the set.
+// The second return value is false if no line has the metric.
+func (s SampleSet) Summary(unit string) (Summary, bool) {
+	values := s.Values(unit)
+	if len(values) == 0 {
+		return Summary{}, false
+	}
+	return Summarize(values), true
+}
+
+// tQuantile returns the p-quantile of Student's t-distribution with df degrees of freedom, for p in (0.5, 1).
+// It inverts studentTCDF by bisection.
+func tQuantile(p, df float64) float64 {
+	lo, hi := 0.0, 1.0
+	for studentTCDF(hi, df) < p {
+		hi *= 2
+	}
+	for i := 0; i < 100; i++ {
+		mid := (lo + hi) / 2
+		if studentTCDF(mid, df) < p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
+
+// studentTCDF returns the cumulative distribution function of Student's t-distribution
+// with df degrees of freedom at t.
+func studentTCDF(t, df float64) float64 {
+	x := df / (df + t*t)
+	tail := 0.5 * regIncBeta(df/2, 0.5, x)
+	if t > 0 {
+		return 1 - tail
+	}
+	return tail
+}
+
+// regIncBeta returns the regularized incomplete beta function I_x(a, b).
+// It uses the continued fraction expansion evaluated with the modified Lentz method.
+func regIncBeta(a, b, x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	if x > (a+1)/(a+b+2) {
+		return 1 - regIncBeta(b, a, 1-x)
+	}
+
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	front := math.Exp(lab-la-lb+a*math.Log(x)+b*math.Log(1-x)) / a
+
+	const tiny = 1e-300
+	f, c, d := 1.0, 1.0, 0.0
+	for i := 0; i <= 300; i++ {
+		m := float64(i / 2)
+		var num float64
+		switch {
+		case i == 0:
+			num = 1
+		case i%2 == 0:
+			num = m * (b - m) * x / ((a + 2*m - 1) * (a + 2*m))
+		default:
+			num = -(a + m) * (a + b + m) * x / ((a + 2*m) * (a + 2*m + 1))
+		}
+
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		d = 1 / d
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		f *= c * d
+		if math.Abs(1-c*d) < 1e-12 {
+			return front * (f - 1)
+		}
+	}
+	return front * (f - 1)
+}
@@ -0,0 +1,82 @@
+package benchutil
+
+import (
+	"math"
+	"testing"
+)
+
+func approxEqual(a, b, tol float64) bool {
+	return math.Abs(a-b) <= tol
+}
+
+func TestSummarize(t *testing.T) {
+	s := Summarize([]float64{10, 12, 9, 11, 13})
+	if s.N != 5 || s.Mean != 11 || s.Median != 11 || s.Min != 9 || s.Max != 13 {
+		t.Errorf("Summarize() = %+v", s)
+	}
+	if !approxEqual(s.StdDev, 1.5811, 1e-4) {
+		t.Errorf("StdDev = %v, want 1.5811", s.StdDev)
+	}
+	// t(0.975, 4) = 2.7764
+	if !approxEqual(s.CI95, 2.7764*1.5811/math.Sqrt(5), 1e-3) {
+		t.Errorf("CI95 = %v, want %v", s.CI95, 2.7764*1.5811/math.Sqrt(5))
+	}
+	if !approxEqual(s.CV, 14.374, 1e-3) {
+		t.Errorf("CV = %v, want 14.374", s.CV)
+	}
+
+	s = Summarize([]float64{4, 1, 3, 2})
+	if s.Median != 2.5 {
+		t.Errorf("Median = %v, want 2.5", s.Median)
+	}
+
+	s = Summarize([]float64{7})
+	if s.StdDev != 0 || s.CI95 != 0 || s.CV != 0 {
+		t.Errorf("single sample Summarize() = %+v, want no spread", s)
+	}
+
+	if s := Summarize(nil); s.N != 0 {
+		t.Errorf("Summarize(nil) = %+v, want zero", s)
+	}
+}
+
+func TestTQuantile(t *testing.T) {
+	tests := []struct {
+		df   float64
+		want float64
+	}{
+		{1, 12.7062},
+		{4, 2.7764},
+		{9, 2.2622},
+		{30, 2.0423},
+		{1000, 1.9623},
+	}
+
+	for _, tt := range tests {
+		if got := tQuantile(0.975, tt.df); !approxEqual(got, tt.want, 1e-3) {
+			t.Errorf("tQuantile(0.975, %v) = %v, want %v", tt.df, got, tt.want)
+		}
+	}
+}
+
+func TestGroupSamples(t *testing.T) {
+	lines := mustParseLines(t,
+		"BenchmarkA-8   100   1000 ns/op   100 B/op",
+		"BenchmarkB-8   100   500 ns/op   7 MB/s",
+		"BenchmarkA-8   100   1200 ns/op   100 B/op",
+	)
+
+	sets := GroupSamples(lines)
+	if len(sets) != 2 || sets[0].Name != "BenchmarkA-8" || len(sets[0].Lines) != 2 || len(sets[1].Lines) != 1 {
+		t.Fatalf("GroupSamples() = %+v", sets)
+	}
+	if s, ok := sets[0].Summary("ns/op"); !ok || s.Mean != 1100 {
+		t.Errorf("Summary(ns/op) = %+v, %v, want mean 1100", s, ok)
+	}
+	if _, ok := sets[0].Summary("MB/s"); ok {
+		t.Errorf("Summary(MB/s) should be missing for BenchmarkA-8")
+	}
+	if units := sets[1].Units(); len(units) != 2 || units[1] != "MB/s" {
+		t.Errorf("Units() = %v, want [ns/op MB/s]", units)
+	}
+}
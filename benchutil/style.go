@@ -0,0 +1,178 @@
+package benchutil
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/rah-0/testmark/model"
+)
+
+// Style selects how durations and byte sizes are written in human-readable form.
+// The zero value is the default style: a full breakdown with binary byte prefixes, e.g. "3ms 651µs 349ns" and "1KiB 24B".
+type Style struct {
+	// Compact writes a single unit with three significant figures, e.g. "3.65ms" and "1.02KiB", instead of a breakdown
+	Compact bool
+	// SI writes byte sizes with decimal prefixes (kB, MB, GB) instead of binary prefixes (KiB, MiB, GiB)
+	SI bool
+	// ASCII writes "us" instead of "µs" for terminals and log systems that mangle UTF-8
+	ASCII bool
+}
+
+// psScale lists the time units of Style.NsFloat in picoseconds, from the largest to the smallest:
+// the units of model.DurationUnits followed by "ps".
+var psScale = picoseconds(model.DurationUnits)
+
+// maxPsNs is the largest duration in nanoseconds that NsFloat converts to whole picoseconds without overflowing int64.
+const maxPsNs = float64(math.MaxInt64 / 1000)
+
+// siScale lists the decimal byte units of the SI style from the largest to the smallest.
+// The binary units of the default style are model.SizeUnits.
+var siScale = []model.Unit{
+	{Name: "EB", Size: model.EB},
+	{Name: "PB", Size: model.PB},
+	{Name: "TB", Size: model.TB},
+	{Name: "GB", Size: model.GB},
+	{Name: "MB", Size: model.MB},
+	{Name: "kB", Size: model.KB},
+	{Name: "B", Size: 1},
+}
+
+// Ns formats a duration in nanoseconds.
+// The default style is the breakdown of HumanNs.
+func (s Style) Ns(ns int64) string {
+	out := model.Duration(ns).String()
+	if s.Compact {
+		out = compactScale(float64(ns), model.DurationUnits)
+	}
+	if s.ASCII {
+		out = strings.ReplaceAll(out, "µs", "us")
+	}
+	return out
+}
+
+// NsFloat formats a fractional duration in nanoseconds with picosecond precision, e.g. "251ps" for 0.2513
+// and "12ns 750ps" for 12.75, or "12.8ns" in the compact style. Whole nanoseconds are written like Ns.
+// A non-zero duration below half a picosecond is written as "<1ps" rather than rounded to zero.
+// Durations beyond the int64 range are clamped to it, NaN is written as "NaN".
+func (s Style) NsFloat(ns float64) string {
+	if math.IsNaN(ns) {
+		return "NaN"
+	}
+	if math.Abs(ns) > maxPsNs {
+		return s.Ns(clampInt64(ns))
+	}
+	ps := int64(math.Round(ns * 1000))
+	if ps == 0 && ns > 0 {
+		return "<1ps"
+	}
+	if ps == 0 && ns < 0 {
+		return "-<1ps"
+	}
+	if ps%1000 == 0 {
+		return s.Ns(ps / 1000)
+	}
+
+	var out string
+	if s.Compact {
+		out = compactScale(float64(ps), psScale)
+	} else {
+		out = model.Breakdown(ps, psScale)
+	}
+	if s.ASCII {
+		out = strings.ReplaceAll(out, "µs", "us")
+	}
+	return out
+}
+
+// Bytes formats a byte count.
+// The default style is the breakdown of HumanBytes.
+func (s Style) Bytes(b int64) string {
+	scale := model.SizeUnits
+	if s.SI {
+		scale = siScale
+	}
+	switch {
+	case s.Compact:
+		return compactScale(float64(b), scale)
+	case s.SI:
+		return model.Breakdown(b, scale)
+	default:
+		return model.Size(b).String()
+	}
+}
+
+// Value formats a value of the given metric unit like HumanValue, using the style for time and byte units.
+func (s Style) Value(unit string, v float64) string {
+	kind, scale := classifyUnit(unit)
+	switch {
+	case kind == unitTime:
+		return s.NsFloat(v * scale)
+	case kind == unitBytes && strings.HasSuffix(unit, "/s"):
+		return HumanThroughput(v * scale)
+	case kind == unitBytes:
+		return s.Bytes(clampInt64(v * scale))
+	default:
+		return formatNumber(v)
+	}
+}
+
+// picoseconds returns the time units in picoseconds followed by "ps", from the largest to the smallest.
+func picoseconds(units []model.Unit) []model.Unit {
+	out := make([]model.Unit, 0, len(units)+1)
+	for _, u := range units {
+		out = append(out, model.Unit{Name: u.Name, Size: 1000 * u.Size})
+	}
+	return append(out, model.Unit{Name: "ps", Size: 1})
+}
+
+// clampInt64 converts v to an int64, clamping it to the int64 range. NaN becomes 0.
+func clampInt64(v float64) int64 {
+	switch {
+	case math.IsNaN(v):
+		return 0
+	case v >= math.MaxInt64:
+		return math.MaxInt64
+	case v <= math.MinInt64:
+		return math.MinInt64
+	default:
+		return int64(v)
+	}
+}
+
+// compactScale writes v in the largest unit of the scale it reaches, with three significant figures, e.g. "3.65ms".
+// A value that rounds up to the next unit is written in that unit, so 999.9µs becomes "1ms".
+func compactScale(v float64, scale []model.Unit) string {
+	if v < 0 {
+		return "-" + compactScale(-v, scale)
+	}
+	i := len(scale) - 1
+	for i > 0 && v >= float64(scale[i-1].Size) {
+		i--
+	}
+	num := sigFigs(v/float64(scale[i].Size), 3)
+	if i > 0 {
+		if f, _ := strconv.ParseFloat(num, 64); f >= float64(scale[i-1].Size/scale[i].Size) {
+			i--
+			num = sigFigs(v/float64(scale[i].Size), 3)
+		}
+	}
+	return num + scale[i].Name
+}
+
+// sigFigs formats a non-negative v with n significant figures for values below 10^n,
+// and without decimals above, trimming trailing zeros.
+func sigFigs(v float64, n int) string {
+	decimals := 0
+	if v > 0 {
+		decimals = n - 1 - int(math.Floor(math.Log10(v)))
+	}
+	if decimals < 0 {
+		decimals = 0
+	}
+	num := strconv.FormatFloat(v, 'f', decimals, 64)
+	if strings.Contains(num, ".") {
+		num = strings.TrimRight(strings.TrimRight(num, "0"), ".")
+	}
+	return num
+}
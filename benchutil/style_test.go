@@ -0,0 +1,108 @@
+package benchutil
+
+import (
+	"testing"
+)
+
+func TestStyleNs(t *testing.T) {
+	tests := []struct {
+		style Style
+		ns    int64
+		want  string
+	}{
+		{Style{}, 3651349, "3ms 651µs 349ns"},
+		{Style{ASCII: true}, 3651349, "3ms 651us 349ns"},
+		{Style{Compact: true}, 3651349, "3.65ms"},
+		{Style{Compact: true, ASCII: true}, 114430, "114us"},
+		{Style{Compact: true}, 0, "0ns"},
+		{Style{Compact: true}, 999, "999ns"},
+		{Style{Compact: true}, 999999, "1ms"},
+		{Style{Compact: true}, 1500, "1.5µs"},
+		{Style{Compact: true}, 90 * 1e9, "1.5m"},
+		{Style{Compact: true}, 59999 * 1e6, "1m"},
+		{Style{Compact: true}, 7200 * 1e9, "2h"},
+		{Style{Compact: true}, -1500, "-1.5µs"},
+	}
+	for _, tt := range tests {
+		if got := tt.style.Ns(tt.ns); got != tt.want {
+			t.Errorf("%+v.Ns(%d) = %q, want %q", tt.style, tt.ns, got, tt.want)
+		}
+	}
+}
+
+func TestStyleNsFloat(t *testing.T) {
+	tests := []struct {
+		style Style
+		ns    float64
+		want  string
+	}{
+		{Style{Compact: true}, 0.2513, "251ps"},
+		{Style{Compact: true}, 12.75, "12.8ns"},
+		{Style{Compact: true}, 999.9996, "1µs"},
+		{Style{Compact: true}, 0.0001, "<1ps"},
+		{Style{ASCII: true}, 1500.5, "1us 500ns 500ps"},
+	}
+	for _, tt := range tests {
+		if got := tt.style.NsFloat(tt.ns); got != tt.want {
+			t.Errorf("%+v.NsFloat(%v) = %q, want %q", tt.style, tt.ns, got, tt.want)
+		}
+	}
+}
+
+func TestStyleBytes(t *testing.T) {
+	tests := []struct {
+		style Style
+		b     int64
+		want  string
+	}{
+		{Style{}, 1048, "1KiB 24B"},
+		{Style{SI: true}, 1048, "1kB 48B"},
+		{Style{SI: true}, 2500000, "2MB 500kB"},
+		{Style{Compact: true}, 1048, "1.02KiB"},
+		{Style{Compact: true}, 808, "808B"},
+		{Style{Compact: true, SI: true}, 1048, "1.05kB"},
+		{Style{Compact: true, SI: true}, 999999, "1MB"},
+		{Style{Compact: true}, 3 << 30, "3GiB"},
+		{Style{ASCII: true}, 1048, "1KiB 24B"},
+	}
+	for _, tt := range tests {
+		if got := tt.style.Bytes(tt.b); got != tt.want {
+			t.Errorf("%+v.Bytes(%d) = %q, want %q", tt.style, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestStyleValue(t *testing.T) {
+	s := Style{Compact: true, SI: true, ASCII: true}
+	tests := []struct {
+		unit string
+		v    float64
+		want string
+	}{
+		{"ns/op", 2500, "2.5us"},
+		{"ms/op", 1.5, "1.5ms"},
+		{"B/op", 2048, "2.05kB"},
+		{"MB/s", 512, "512MB/s"},
+		{"allocs/op", 18, "18"},
+	}
+	for _, tt := range tests {
+		if got := s.Value(tt.unit, tt.v); got != tt.want {
+			t.Errorf("Value(%q, %v) = %q, want %q", tt.unit, tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestFormatterStyle(t *testing.T) {
+	f := NewFormatter().SetStyle(Style{Compact: true})
+	line := "BenchmarkSample/Count1000-8   100000   3651349 ns/op   1048 B/op   18 allocs/op"
+	want := "BenchmarkSample/Count1000-8\t100000\t3651349 ns/op\t1048 B/op\t18 allocs/op\tCPU[3.65ms]\tMEM[1.02KiB]"
+	bl, _ := ParseLine(line)
+	if got := f.FormatLine(bl); got != want {
+		t.Errorf("FormatLine() = %q, want %q", got, want)
+	}
+
+	r := f.Record(line)
+	if r.CPU != "3.65ms" || r.MEM != "1.02KiB" {
+		t.Errorf("Record() CPU, MEM = %q, %q, want 3.65ms, 1.02KiB", r.CPU, r.MEM)
+	}
+}
@@ -0,0 +1,260 @@
+package benchutil
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"strings"
+)
+
+// svgPalette are the series colors of multi-series charts.
+var svgPalette = []string{"#4e79a7", "#f28e2b", "#e15759", "#76b7b2", "#59a14f", "#edc948", "#b07aa1", "#ff9da7", "#9c755f", "#bab0ac"}
+
+// svgChart is the content of a single chart and its size in pixels.
+type svgChart struct {
+	width  int
+	height int
+	body   string
+}
+
+// String renders the chart as a standalone SVG element.
+func (c svgChart) String() string {
+	return svgOpen(c.width, c.height) + c.body + `</svg>`
+}
+
+// svgOpen returns the opening tag of an SVG element of the given size.
+func svgOpen(width, height int) string {
+	return fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`, width, height, width, height)
+}
+
+// svgStack renders the charts one below the other as a single SVG document.
+func svgStack(charts []svgChart) string {
+	const gap = 16
+	width, height := 0, 0
+	for i, c := range charts {
+		if c.width > width {
+			width = c.width
+		}
+		if i > 0 {
+			height += gap
+		}
+		height += c.height
+	}
+
+	var sb strings.Builder
+	sb.WriteString(svgOpen(width, height))
+	y := 0
+	for _, c := range charts {
+		fmt.Fprintf(&sb, `<g transform="translate(0,%d)">%s</g>`, y, c.body)
+		y += c.height + gap
+	}
+	sb.WriteString(`</svg>`)
+	return sb.String()
+}
+
+// barChartItem is a single labeled bar of a bar chart.
+type barChartItem struct {
+	Label string
+	Value float64
+}
+
+// svgBarChart renders a horizontal bar chart as an inline SVG element.
+// Bars are scaled to the largest value and labeled with format applied to their value.
+func svgBarChart(title string, items []barChartItem, format func(float64) string) string {
+	return barChart(title, items, format).String()
+}
+
+// barChart lays out a horizontal bar chart. Bars are scaled to the largest value
+// and labeled with format applied to their value.
+func barChart(title string, items []barChartItem, format func(float64) string) svgChart {
+	const (
+		labelWidth = 220
+		barWidth   = 360
+		valueWidth = 140
+		rowHeight  = 22
+		top        = 28
+	)
+	width := labelWidth + barWidth + valueWidth
+	height := top + rowHeight*len(items) + 8
+
+	max := 0.0
+	for _, it := range items {
+		if it.Value > max {
+			max = it.Value
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<text x="0" y="16" font-weight="bold">%s</text>`, html.EscapeString(title))
+	for i, it := range items {
+		y := top + i*rowHeight
+		w := 0.0
+		if max > 0 {
+			w = it.Value / max * barWidth
+		}
+		fmt.Fprintf(&sb, `<text x="%d" y="%d" text-anchor="end">%s</text>`, labelWidth-6, y+15, html.EscapeString(it.Label))
+		fmt.Fprintf(&sb, `<rect x="%d" y="%d" width="%.1f" height="%d" fill="%s"><title>%s</title></rect>`,
+			labelWidth, y+3, w, rowHeight-6, svgPalette[0], html.EscapeString(format(it.Value)))
+		fmt.Fprintf(&sb, `<text x="%.1f" y="%d">%s</text>`, float64(labelWidth)+w+6, y+15, html.EscapeString(format(it.Value)))
+	}
+	return svgChart{width: width, height: height, body: sb.String()}
+}
+
+// compareChartItem is a labeled pair of before and after values.
+type compareChartItem struct {
+	Label string
+	Old   float64
+	New   float64
+	// Delta is the change label printed after the bars, e.g. "+10.00%"
+	Delta string
+}
+
+// compareChart lays out a horizontal bar chart with an old and a new bar per item,
+// all scaled to the largest value. Missing values are NaN and draw no bar.
+func compareChart(title string, items []compareChartItem, format func(float64) string) svgChart {
+	const (
+		labelWidth = 220
+		barWidth   = 360
+		valueWidth = 200
+		barHeight  = 14
+		rowHeight  = 2*barHeight + 10
+		top        = 48
+	)
+	width := labelWidth + barWidth + valueWidth
+	height := top + rowHeight*len(items) + 4
+
+	max := 0.0
+	for _, it := range items {
+		max = math.Max(max, math.Max(nanToZero(it.Old), nanToZero(it.New)))
+	}
+
+	colors := [2]string{svgPalette[9], svgPalette[0]}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<text x="0" y="16" font-weight="bold">%s</text>`, html.EscapeString(title))
+	for i, name := range []string{"old", "new"} {
+		x := labelWidth + i*70
+		fmt.Fprintf(&sb, `<rect x="%d" y="26" width="12" height="12" fill="%s"/><text x="%d" y="36">%s</text>`, x, colors[i], x+16, name)
+	}
+	for i, it := range items {
+		y := top + i*rowHeight
+		fmt.Fprintf(&sb, `<text x="%d" y="%d" text-anchor="end">%s</text>`, labelWidth-6, y+barHeight+4, html.EscapeString(it.Label))
+		end := 0.0
+		for j, v := range []float64{it.Old, it.New} {
+			if math.IsNaN(v) {
+				continue
+			}
+			w := 0.0
+			if max > 0 {
+				w = v / max * barWidth
+			}
+			end = math.Max(end, w)
+			fmt.Fprintf(&sb, `<rect x="%d" y="%d" width="%.1f" height="%d" fill="%s"><title>%s</title></rect>`,
+				labelWidth, y+j*barHeight, w, barHeight-2, colors[j], html.EscapeString(format(v)))
+		}
+		label := it.Delta
+		if !math.IsNaN(it.New) {
+			label = format(it.New) + "  " + label
+		}
+		fmt.Fprintf(&sb, `<text x="%.1f" y="%d">%s</text>`, float64(labelWidth)+end+6, y+barHeight+4, html.EscapeString(label))
+	}
+	return svgChart{width: width, height: height, body: sb.String()}
+}
+
+// nanToZero returns 0 for NaN and v otherwise.
+func nanToZero(v float64) float64 {
+	if math.IsNaN(v) {
+		return 0
+	}
+	return v
+}
+
+// lineSeries is one named line of a line chart.
+type lineSeries struct {
+	Name   string
+	Points []ComplexityPoint
+}
+
+// lineChart lays out a line chart with logarithmic axes, one line per series with points ordered by N.
+// Both axes span whole decades and carry a tick per decade, labeled with xFormat and yFormat.
+// Points with a non-positive N or value cannot be placed on a log scale and are skipped.
+func lineChart(title string, series []lineSeries, xFormat, yFormat func(float64) string) svgChart {
+	const (
+		left        = 90
+		plotWidth   = 480
+		plotHeight  = 280
+		legendWidth = 260
+		top         = 32
+		bottom      = 36
+	)
+	width := left + plotWidth + 16 + legendWidth
+	height := top + plotHeight + bottom
+	if h := top + 20*len(series) + 8; h > height {
+		height = h
+	}
+
+	xlo, xhi := math.Inf(1), math.Inf(-1)
+	ylo, yhi := math.Inf(1), math.Inf(-1)
+	for _, s := range series {
+		for _, p := range s.Points {
+			if p.N > 0 && p.Value > 0 {
+				xlo, xhi = math.Min(xlo, p.N), math.Max(xhi, p.N)
+				ylo, yhi = math.Min(ylo, p.Value), math.Max(yhi, p.Value)
+			}
+		}
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<text x="0" y="16" font-weight="bold">%s</text>`, html.EscapeString(title))
+	if math.IsInf(xlo, 1) {
+		return svgChart{width: width, height: height, body: sb.String()}
+	}
+
+	xd0, xd1 := decades(xlo, xhi)
+	yd0, yd1 := decades(ylo, yhi)
+	px := func(n float64) float64 {
+		return left + (math.Log10(n)-float64(xd0))/float64(xd1-xd0)*plotWidth
+	}
+	py := func(v float64) float64 {
+		return top + plotHeight - (math.Log10(v)-float64(yd0))/float64(yd1-yd0)*plotHeight
+	}
+
+	fmt.Fprintf(&sb, `<rect x="%d" y="%d" width="%d" height="%d" fill="none" stroke="#999"/>`, left, top, plotWidth, plotHeight)
+	for d := xd0; d <= xd1; d++ {
+		x := px(math.Pow(10, float64(d)))
+		fmt.Fprintf(&sb, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="#e0e0e0"/>`, x, top, x, top+plotHeight)
+		fmt.Fprintf(&sb, `<text x="%.1f" y="%d" text-anchor="middle">%s</text>`, x, top+plotHeight+16, html.EscapeString(xFormat(math.Pow(10, float64(d)))))
+	}
+	for d := yd0; d <= yd1; d++ {
+		y := py(math.Pow(10, float64(d)))
+		fmt.Fprintf(&sb, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="#e0e0e0"/>`, left, y, left+plotWidth, y)
+		fmt.Fprintf(&sb, `<text x="%d" y="%.1f" text-anchor="end">%s</text>`, left-6, y+4, html.EscapeString(yFormat(math.Pow(10, float64(d)))))
+	}
+
+	for i, s := range series {
+		color := svgPalette[i%len(svgPalette)]
+		var coords []string
+		for _, p := range s.Points {
+			if p.N <= 0 || p.Value <= 0 {
+				continue
+			}
+			coords = append(coords, fmt.Sprintf("%.1f,%.1f", px(p.N), py(p.Value)))
+			fmt.Fprintf(&sb, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s"><title>%s: %s</title></circle>`,
+				px(p.N), py(p.Value), color, html.EscapeString(xFormat(p.N)), html.EscapeString(yFormat(p.Value)))
+		}
+		fmt.Fprintf(&sb, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`, strings.Join(coords, " "), color)
+
+		ly := top + i*20
+		fmt.Fprintf(&sb, `<rect x="%d" y="%d" width="12" height="12" fill="%s"/>`, left+plotWidth+16, ly, color)
+		fmt.Fprintf(&sb, `<text x="%d" y="%d">%s</text>`, left+plotWidth+32, ly+10, html.EscapeString(s.Name))
+	}
+	return svgChart{width: width, height: height, body: sb.String()}
+}
+
+// decades returns the powers of ten enclosing lo and hi, at least one decade apart.
+func decades(lo, hi float64) (int, int) {
+	d0 := int(math.Floor(math.Log10(lo)))
+	d1 := int(math.Ceil(math.Log10(hi)))
+	if d1 <= d0 {
+		d1 = d0 + 1
+	}
+	return d0, d1
+}
@@ -0,0 +1,117 @@
+package benchutil
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// WriteBarChartSVG writes a standalone SVG document with one horizontal bar chart per benchmark group
+// (see BenchLine.Group), showing the mean of the metric with the given unit for every benchmark.
+// Values and labels are formatted with HumanValue. It returns an error if no benchmark has the metric.
+func WriteBarChartSVG(w io.Writer, lines []BenchLine, unit string) error {
+	var charts []svgChart
+	var titles []string
+	items := map[string][]barChartItem{}
+	for _, set := range GroupSamples(lines) {
+		s, ok := set.Summary(unit)
+		if !ok {
+			continue
+		}
+		group := set.Lines[0].Group()
+		if _, ok := items[group]; !ok {
+			titles = append(titles, group)
+		}
+		label := strings.TrimPrefix(set.Name, group+"/")
+		items[group] = append(items[group], barChartItem{Label: label, Value: s.Mean})
+	}
+	if len(titles) == 0 {
+		return fmt.Errorf("no benchmark has the metric %q", unit)
+	}
+
+	format := func(v float64) string { return HumanValue(unit, v) }
+	for _, g := range titles {
+		charts = append(charts, barChart(g+" ("+unit+")", items[g], format))
+	}
+	_, err := io.WriteString(w, svgStack(charts)+"\n")
+	return err
+}
+
+// WriteSweepChartSVG writes a standalone SVG line chart with logarithmic axes of the metric with the given unit
+// over a numeric name dimension, one line per parameter sweep as grouped by AnalyzeComplexity.
+// If key is empty, the dimension of the last name segment is used. Repeated runs are averaged.
+// The y axis is labeled with HumanValue, so ns/op and B/op ticks read like "1µs" and "1KiB".
+// It returns an error if no benchmark has both the dimension and the metric.
+func WriteSweepChartSVG(w io.Writer, lines []BenchLine, key, unit string) error {
+	var series []lineSeries
+	index := map[string]int{}
+	values := map[string]map[float64][]float64{}
+	for _, l := range lines {
+		m, ok := l.Metric(unit)
+		if !ok {
+			continue
+		}
+		name, d, ok := sweepName(l, key)
+		if !ok || !d.Numeric {
+			continue
+		}
+		if _, ok := index[name]; !ok {
+			index[name] = len(series)
+			series = append(series, lineSeries{Name: name})
+			values[name] = map[float64][]float64{}
+		}
+		values[name][d.Number] = append(values[name][d.Number], m.Value)
+	}
+	if len(series) == 0 {
+		return fmt.Errorf("no benchmark has a numeric name dimension and the metric %q", unit)
+	}
+
+	for i := range series {
+		for n, vs := range values[series[i].Name] {
+			series[i].Points = append(series[i].Points, ComplexityPoint{N: n, Value: Summarize(vs).Mean})
+		}
+		sort.Slice(series[i].Points, func(a, b int) bool { return series[i].Points[a].N < series[i].Points[b].N })
+	}
+
+	chart := lineChart(unit, series,
+		func(v float64) string { return humanDecimal(v, "") },
+		func(v float64) string { return HumanValue(unit, v) })
+	_, err := io.WriteString(w, chart.String()+"\n")
+	return err
+}
+
+// WriteCompareChartSVG writes a standalone SVG chart with an old and a new bar per comparison for the metric
+// with the given unit, labeled with the new mean and the change in percent.
+// It returns an error if no comparison has the metric on either side.
+func WriteCompareChartSVG(w io.Writer, comps []Comparison, unit string) error {
+	var items []compareChartItem
+	for _, c := range comps {
+		it := compareChartItem{Label: c.Name, Old: math.NaN(), New: math.NaN()}
+		if c.Old != nil {
+			if s, ok := c.Old.Summary(unit); ok {
+				it.Old = s.Mean
+			}
+		}
+		if c.New != nil {
+			if s, ok := c.New.Summary(unit); ok {
+				it.New = s.Mean
+			}
+		}
+		if math.IsNaN(it.Old) && math.IsNaN(it.New) {
+			continue
+		}
+		if pct, ok := c.DeltaPct(unit); ok {
+			it.Delta = FormatDeltaPct(pct)
+		}
+		items = append(items, it)
+	}
+	if len(items) == 0 {
+		return fmt.Errorf("no benchmark has the metric %q", unit)
+	}
+
+	chart := compareChart(unit, items, func(v float64) string { return HumanValue(unit, v) })
+	_, err := io.WriteString(w, chart.String()+"\n")
+	return err
+}
@@ -0,0 +1,102 @@
+package benchutil
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+)
+
+// checkSVG fails the test if out is not a well-formed XML document or lacks any of the wanted fragments.
+func checkSVG(t *testing.T, out string, want ...string) {
+	t.Helper()
+	dec := xml.NewDecoder(strings.NewReader(out))
+	for {
+		if _, err := dec.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("invalid SVG: %v\n%s", err, out)
+		}
+	}
+	for _, w := range want {
+		if !strings.Contains(out, w) {
+			t.Errorf("SVG does not contain %q:\n%s", w, out)
+		}
+	}
+}
+
+func TestWriteBarChartSVG(t *testing.T) {
+	lines := mustParseLines(t,
+		"BenchmarkSample/Count10-8   1000   500 ns/op",
+		"BenchmarkSample/Count100-8   1000   1000 ns/op",
+		"BenchmarkSample/Count100-8   1000   3000 ns/op",
+		"BenchmarkOther-8   1000   50 ns/op",
+	)
+	var buf bytes.Buffer
+	if err := WriteBarChartSVG(&buf, lines, "ns/op"); err != nil {
+		t.Fatal(err)
+	}
+	checkSVG(t, buf.String(),
+		`<svg xmlns="http://www.w3.org/2000/svg" width="720" height="`,
+		">BenchmarkSample (ns/op)</text>",
+		">Count10-8</text>",
+		">2µs</text>",
+		`<g transform="translate(0,96)">`,
+		">BenchmarkOther (ns/op)</text>",
+	)
+
+	if err := WriteBarChartSVG(&buf, lines, "B/op"); err == nil {
+		t.Error("WriteBarChartSVG(B/op) succeeded without any B/op metric, want error")
+	}
+}
+
+func TestWriteSweepChartSVG(t *testing.T) {
+	lines := mustParseLines(t,
+		"BenchmarkEncode/size=10/codec=zstd-8   1000   20 ns/op",
+		"BenchmarkEncode/size=1000/codec=zstd-8   1000   2000 ns/op",
+		"BenchmarkEncode/size=100/codec=zstd-8   1000   200 ns/op",
+		"BenchmarkEncode/size=10/codec=gzip-8   1000   40 ns/op",
+		"BenchmarkEncode/size=1000/codec=gzip-8   1000   4000 ns/op",
+	)
+	var buf bytes.Buffer
+	if err := WriteSweepChartSVG(&buf, lines, "size", "ns/op"); err != nil {
+		t.Fatal(err)
+	}
+	checkSVG(t, buf.String(),
+		">BenchmarkEncode/size=*/codec=zstd-8</text>",
+		">BenchmarkEncode/size=*/codec=gzip-8</text>",
+		`text-anchor="end">10ns</text>`,
+		`text-anchor="end">10µs</text>`,
+		`text-anchor="middle">1K</text>`,
+		// sizes 10 to 1000 span the x axis, 20ns sits log10(2) of the three decades above the 10ns bottom
+		`<polyline points="90.0,283.9 330.0,190.6 570.0,97.2"`,
+	)
+
+	if err := WriteSweepChartSVG(&buf, lines, "level", "ns/op"); err == nil {
+		t.Error("WriteSweepChartSVG(level) succeeded without such a dimension, want error")
+	}
+}
+
+func TestWriteCompareChartSVG(t *testing.T) {
+	old := mustParseLines(t,
+		"BenchmarkA-8   1000   1000 ns/op",
+		"BenchmarkB-8   1000   500 ns/op",
+	)
+	new := mustParseLines(t,
+		"BenchmarkA-8   1000   1100 ns/op",
+		"BenchmarkC-8   1000   200 ns/op",
+	)
+	var buf bytes.Buffer
+	if err := WriteCompareChartSVG(&buf, Compare(old, new), "ns/op"); err != nil {
+		t.Fatal(err)
+	}
+	checkSVG(t, buf.String(),
+		">old</text>",
+		">new</text>",
+		">BenchmarkA-8</text>",
+		">1µs 100ns  +10.00%</text>",
+		`width="360.0" height="12" fill="#4e79a7"`,
+		">BenchmarkC-8</text>",
+	)
+}
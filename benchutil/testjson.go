@@ -0,0 +1,85 @@
+package benchutil
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// TestEvent is a single event of the go test -json (test2json) stream.
+// Only the fields needed to rebuild the text output are decoded.
+type TestEvent struct {
+	Action  string
+	Package string
+	Test    string
+	Output  string
+}
+
+// ScanTestJSON decodes a go test -json event stream and calls fn for every complete output line,
+// with the package it belongs to and without its trailing newline.
+// Benchmark results are often split across several output events, so output is buffered per package
+// until a newline arrives; partial lines left at the end of the stream are flushed.
+// Lines of the stream that are not JSON events, such as build errors, are passed through with an empty package.
+// If fn returns an error, scanning stops and the error is returned.
+func ScanTestJSON(r io.Reader, fn func(pkg, line string) error) error {
+	var order []string
+	pending := map[string]*strings.Builder{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		raw := scanner.Bytes()
+		var ev TestEvent
+		if len(raw) == 0 || raw[0] != '{' || json.Unmarshal(raw, &ev) != nil {
+			if err := fn("", string(raw)); err != nil {
+				return err
+			}
+			continue
+		}
+		if ev.Action != "output" {
+			continue
+		}
+
+		buf, ok := pending[ev.Package]
+		if !ok {
+			buf = &strings.Builder{}
+			pending[ev.Package] = buf
+			order = append(order, ev.Package)
+		}
+		out := ev.Output
+		for {
+			i := strings.IndexByte(out, '\n')
+			if i < 0 {
+				buf.WriteString(out)
+				break
+			}
+			buf.WriteString(out[:i])
+			line := buf.String()
+			buf.Reset()
+			if err := fn(ev.Package, line); err != nil {
+				return err
+			}
+			out = out[i+1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	for _, pkg := range order {
+		if buf := pending[pkg]; buf.Len() > 0 {
+			if err := fn(pkg, buf.String()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// IsTestJSON reports whether the input starting with prefix looks like a go test -json event stream,
+// i.e. its first non-whitespace character opens a JSON object.
+func IsTestJSON(prefix []byte) bool {
+	s := strings.TrimLeft(string(prefix), " \t\r\n")
+	return strings.HasPrefix(s, "{")
+}
@@ -0,0 +1,62 @@
+package benchutil
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScanTestJSON(t *testing.T) {
+	input := `{"Time":"2024-01-01T00:00:00Z","Action":"start","Package":"example.com/a"}
+{"Action":"output","Package":"example.com/a","Output":"goos: linux\n"}
+{"Action":"output","Package":"example.com/a","Output":"pkg: example.com/a\n"}
+{"Action":"run","Package":"example.com/a","Test":"BenchmarkA"}
+{"Action":"output","Package":"example.com/a","Test":"BenchmarkA","Output":"BenchmarkA-8   \t"}
+{"Action":"output","Package":"example.com/b","Test":"BenchmarkB","Output":"BenchmarkB-8   \t"}
+{"Action":"output","Package":"example.com/a","Test":"BenchmarkA","Output":"  100\t  1500 ns/op\t  64 B/op\n"}
+{"Action":"output","Package":"example.com/b","Test":"BenchmarkB","Output":"  200\t  30 ns/op\n"}
+# example.com/c
+{"Action":"output","Package":"example.com/a","Output":"PASS"}
+{"Action":"pass","Package":"example.com/a"}
+`
+	type line struct{ pkg, text string }
+	var got []line
+	err := ScanTestJSON(strings.NewReader(input), func(pkg, text string) error {
+		got = append(got, line{pkg, text})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []line{
+		{"example.com/a", "goos: linux"},
+		{"example.com/a", "pkg: example.com/a"},
+		{"example.com/a", "BenchmarkA-8   \t  100\t  1500 ns/op\t  64 B/op"},
+		{"example.com/b", "BenchmarkB-8   \t  200\t  30 ns/op"},
+		{"", "# example.com/c"},
+		{"example.com/a", "PASS"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ScanTestJSON() lines = %q, want %q", got, want)
+	}
+
+	if bl, ok := ParseLine(got[2].text); !ok || bl.Iterations != 100 || len(bl.Metrics) != 2 {
+		t.Errorf("rebuilt line does not parse: %+v", bl)
+	}
+
+	stop := errors.New("stop")
+	if err := ScanTestJSON(strings.NewReader(input), func(string, string) error { return stop }); err != stop {
+		t.Errorf("ScanTestJSON() error = %v, want %v", err, stop)
+	}
+}
+
+func TestIsTestJSON(t *testing.T) {
+	if !IsTestJSON([]byte("\n  {\"Action\":\"start\"}")) {
+		t.Errorf("expected JSON input to be detected")
+	}
+	if IsTestJSON([]byte("goos: linux\n")) {
+		t.Errorf("expected text input not to be detected as JSON")
+	}
+}
@@ -0,0 +1,145 @@
+package benchutil
+
+import (
+	"strings"
+)
+
+// unitKind classifies the quantity a metric unit measures.
+type unitKind int
+
+const (
+	unitOther unitKind = iota
+	unitTime
+	unitBytes
+)
+
+// timeUnits maps time unit symbols to their size in nanoseconds.
+var timeUnits = map[string]float64{
+	"ns": 1,
+	"µs": 1e3,
+	"us": 1e3,
+	"ms": 1e6,
+	"s":  1e9,
+}
+
+// byteUnits maps byte unit symbols to their size in bytes.
+// Decimal prefixes follow the Go testing package, which reports MB/s as 1e6 bytes per second.
+var byteUnits = map[string]float64{
+	"B":     1,
+	"byte":  1,
+	"bytes": 1,
+	"KB":    1e3,
+	"MB":    1e6,
+	"GB":    1e9,
+	"KiB":   1 << 10,
+	"MiB":   1 << 20,
+	"GiB":   1 << 30,
+	"TB":    1e12,
+	"TiB":   1 << 40,
+	"PB":    1e15,
+	"PiB":   1 << 50,
+	"EB":    1e18,
+	"EiB":   1 << 60,
+}
+
+// Annotation is a human-readable representation of a single metric,
+// rendered as Label[Human], for example "CPU[3ms 651µs 349ns]".
+type Annotation struct {
+	// Label is the bracket label, "CPU" for ns/op, "MEM" for B/op, or the unit itself for custom metrics
+	Label string `json:"label"`
+	// Unit is the unit of the metric the annotation was derived from
+	Unit string `json:"unit"`
+	// Human is the formatted value, e.g. "3ms 651µs 349ns"
+	Human string `json:"human"`
+}
+
+// String returns the annotation in Label[Human] form.
+func (a Annotation) String() string {
+	return a.Label + "[" + a.Human + "]"
+}
+
+// HumanValue formats a value of the given metric unit in human-readable form.
+// Time units are formatted with HumanNsFloat, byte rates with HumanThroughput and byte units with HumanBytes.
+// Values of any other unit are printed as plain numbers with up to two decimals.
+func HumanValue(unit string, v float64) string {
+	return Style{}.Value(unit, v)
+}
+
+// Annotate returns the human-readable annotations for the metrics of a benchmark line
+// using the default Formatter.
+func Annotate(bl BenchLine) []Annotation {
+	return NewFormatter().Annotate(bl)
+}
+
+// annotate builds the annotation for a single metric, formatting time and byte units with the given style.
+// The second return value is false if the metric has no useful human form.
+func annotate(m Metric, style Style) (Annotation, bool) {
+	kind, scale := classifyUnit(m.Unit)
+	label := m.Unit
+	switch m.Unit {
+	case "ns/op":
+		label = "CPU"
+	case "B/op":
+		label = "MEM"
+	}
+
+	var human string
+	switch kind {
+	case unitTime:
+		v := m.Value * scale
+		if v == 0 {
+			return Annotation{}, false
+		}
+		human = style.NsFloat(v)
+	case unitBytes:
+		if strings.HasSuffix(m.Unit, "/s") {
+			if m.Value == 0 {
+				return Annotation{}, false
+			}
+			human = HumanThroughput(m.Value * scale)
+			if strings.TrimLeft(human, "0123456789.") == m.Unit {
+				return Annotation{}, false
+			}
+			return Annotation{Label: "IO", Unit: m.Unit, Human: human}, true
+		}
+		v := clampInt64(m.Value * scale)
+		if v == 0 {
+			return Annotation{}, false
+		}
+		human = style.Bytes(v)
+	default:
+		return Annotation{}, false
+	}
+
+	if scale == 1 && (human == m.Raw+"ns" || human == m.Raw+"B") {
+		return Annotation{}, false
+	}
+	return Annotation{Label: label, Unit: m.Unit, Human: human}, true
+}
+
+// IsTimeUnit reports whether the leading quantity of a metric unit is a time unit, e.g. "ns/op", "µs/op" or "p99-ns".
+func IsTimeUnit(unit string) bool {
+	kind, _ := classifyUnit(unit)
+	return kind == unitTime
+}
+
+// classifyUnit extracts the leading quantity of a metric unit and reports its kind
+// and scale relative to nanoseconds or bytes.
+// The quantity is the part before the first "/", and for units like "p99-ns" the part after the last "-".
+func classifyUnit(unit string) (kind unitKind, scale float64) {
+	q := unit
+	if i := strings.IndexByte(q, '/'); i >= 0 {
+		q = q[:i]
+	}
+	if i := strings.LastIndexByte(q, '-'); i >= 0 {
+		q = q[i+1:]
+	}
+
+	if f, ok := timeUnits[q]; ok {
+		return unitTime, f
+	}
+	if f, ok := byteUnits[q]; ok {
+		return unitBytes, f
+	}
+	return unitOther, 0
+}
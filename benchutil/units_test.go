@@ -0,0 +1,70 @@
+package benchutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnnotate(t *testing.T) {
+	bl, _ := ParseLine("BenchmarkX-8   10   1500 ns/op   2048 B/op   3 allocs/op   4096 B/msg   0 ns/key   7 items/s")
+	want := []Annotation{
+		{Label: "CPU", Unit: "ns/op", Human: "1µs 500ns"},
+		{Label: "MEM", Unit: "B/op", Human: "2KiB"},
+		{Label: "B/msg", Unit: "B/msg", Human: "4KiB"},
+	}
+	got := Annotate(bl)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Annotate() = %+v, want %+v", got, want)
+	}
+}
+
+func TestClassifyUnit(t *testing.T) {
+	tests := []struct {
+		unit  string
+		kind  unitKind
+		scale float64
+	}{
+		{"ns/op", unitTime, 1},
+		{"µs/call", unitTime, 1e3},
+		{"p99-ns", unitTime, 1},
+		{"B/op", unitBytes, 1},
+		{"MB/s", unitBytes, 1e6},
+		{"bytes/msg", unitBytes, 1},
+		{"EiB/op", unitBytes, 1 << 60},
+		{"EB/s", unitBytes, 1e18},
+		{"allocs/op", unitOther, 0},
+		{"items/s", unitOther, 0},
+	}
+
+	for _, tt := range tests {
+		kind, scale := classifyUnit(tt.unit)
+		if kind != tt.kind || scale != tt.scale {
+			t.Errorf("classifyUnit(%q) = %v, %v, want %v, %v", tt.unit, kind, scale, tt.kind, tt.scale)
+		}
+		if got := IsTimeUnit(tt.unit); got != (tt.kind == unitTime) {
+			t.Errorf("IsTimeUnit(%q) = %v, want %v", tt.unit, got, tt.kind == unitTime)
+		}
+	}
+}
+
+func TestHumanValue(t *testing.T) {
+	tests := []struct {
+		unit  string
+		value float64
+		want  string
+	}{
+		{"ns/op", 1500, "1µs 500ns"},
+		{"ms/op", 2.5, "2ms 500µs"},
+		{"B/op", 2048, "2KiB"},
+		{"MB/s", 1250.75, "1.25GB/s"},
+		{"allocs/op", 18, "18"},
+		{"allocs/op", 18.333333, "18.33"},
+		{"items/s", 1200, "1200"},
+	}
+
+	for _, tt := range tests {
+		if got := HumanValue(tt.unit, tt.value); got != tt.want {
+			t.Errorf("HumanValue(%q, %v) = %q, want %q", tt.unit, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/rah-0/testmark/benchutil"
+)
+
+// runChart implements "testmark chart": it writes a static SVG chart of benchmark output.
+// The bar and sweep kinds read the given files or stdin, the compare kind reads an old and a new file.
+func runChart(args []string) int {
+	fs := flag.NewFlagSet("testmark chart", flag.ExitOnError)
+	out := fs.String("o", "chart.svg", "path of the SVG file to write")
+	kind := fs.String("kind", "bar", "chart kind: bar (one bar chart per group), sweep (log-scale line chart over a numeric dimension) or compare (old.txt new.txt)")
+	metric := fs.String("metric", "ns/op", "metric unit to chart")
+	dim := fs.String("dim", "", "sweep: name dimension on the x axis (default: the last name segment)")
+	strictEnv := fs.Bool("strict-env", false, "compare: refuse to compare runs from different goos, goarch or cpu instead of warning")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: testmark chart [flags] [files]")
+		fmt.Fprintln(fs.Output(), "       testmark chart -kind compare [flags] old.txt new.txt")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	var buf bytes.Buffer
+	switch *kind {
+	case "bar", "sweep":
+		records, err := readRecords(benchutil.NewFormatter(), fs.Args())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+			return 1
+		}
+		if *kind == "bar" {
+			err = benchutil.WriteBarChartSVG(&buf, benchLines(records), *metric)
+		} else {
+			err = benchutil.WriteSweepChartSVG(&buf, benchLines(records), *dim, *metric)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	case "compare":
+		if fs.NArg() != 2 {
+			fs.Usage()
+			return 2
+		}
+		old, oldEnv, err := readBenchLines(fs.Arg(0))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+			return 1
+		}
+		new, newEnv, err := readBenchLines(fs.Arg(1))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+			return 1
+		}
+		if !checkEnv(oldEnv, newEnv, *strictEnv) {
+			return 2
+		}
+		if err := benchutil.WriteCompareChartSVG(&buf, benchutil.Compare(old, new), *metric); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 1
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown chart kind %q\n", *kind)
+		return 2
+	}
+
+	if err := os.WriteFile(*out, buf.Bytes(), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing chart: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/rah-0/testmark/benchutil"
+)
+
+// percentFlag is a flag.Value that sets an optional percentage threshold.
+type percentFlag struct {
+	p **benchutil.Percent
+}
+
+func (f percentFlag) String() string {
+	if f.p == nil || *f.p == nil {
+		return ""
+	}
+	return (*f.p).String()
+}
+
+func (f percentFlag) Set(s string) error {
+	v, err := benchutil.ParsePercent(s)
+	if err != nil {
+		return err
+	}
+	*f.p = &v
+	return nil
+}
+
+// runCheck implements "testmark check": it compares benchmark output from the given files or stdin
+// against a baseline capture and exits with status 1 if any benchmark regressed beyond its threshold.
+// Baseline benchmarks missing from the current run are warned about. Errors, including a run
+// that shares no benchmark with the baseline, exit with status 2.
+func runCheck(args []string) int {
+	fs := flag.NewFlagSet("testmark check", flag.ExitOnError)
+	baseline := fs.String("baseline", "", "baseline benchmark output to compare against (required)")
+	config := fs.String("config", "", "JSON file with global and per-benchmark thresholds")
+	alpha := fs.Float64("alpha", 0.05, "ignore regressions of repeated runs with a p-value above this level (0 disables)")
+	strictEnv := fs.Bool("strict-env", false, "refuse to check against a baseline from a different goos, goarch or cpu instead of warning")
+	var flags benchutil.Thresholds
+	fs.Var(percentFlag{&flags.Time}, "max-time-regress", "maximum allowed ns/op regression, e.g. 5%")
+	fs.Var(percentFlag{&flags.Mem}, "max-mem-regress", "maximum allowed B/op regression, e.g. 10%")
+	fs.Var(percentFlag{&flags.Allocs}, "max-alloc-regress", "maximum allowed allocs/op regression, e.g. 0")
+	_ = fs.Parse(args)
+
+	if *baseline == "" {
+		fmt.Fprintln(os.Stderr, "Error: -baseline is required")
+		fs.Usage()
+		return 2
+	}
+
+	var cfg benchutil.CheckConfig
+	if *config != "" {
+		file, err := os.Open(*config)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+		cfg, err = benchutil.ReadCheckConfig(file)
+		file.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", *config, err)
+			return 2
+		}
+	}
+	// Thresholds given on the command line take precedence over the global ones from the config file.
+	cfg.Thresholds = cfg.Thresholds.Merge(flags)
+	// An alpha from the config file, including 0, only gives way to an explicit -alpha.
+	if cfg.Alpha == nil || flagSet(fs, "alpha") {
+		cfg.Alpha = alpha
+	}
+
+	old, oldEnv, err := readBenchLines(*baseline)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading baseline: %v\n", err)
+		return 2
+	}
+	records, err := readRecords(benchutil.NewFormatter(), fs.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 2
+	}
+	if !checkEnv(oldEnv, benchutil.FirstEnv(records), *strictEnv) {
+		return 2
+	}
+
+	comparisons := benchutil.Compare(old, benchLines(records))
+	matched := 0
+	for _, c := range comparisons {
+		switch {
+		case c.Old != nil && c.New != nil:
+			matched++
+		case c.Old != nil:
+			fmt.Fprintf(os.Stderr, "Warning: %s is missing from the current run\n", c.Name)
+		}
+	}
+	// An empty or unrelated run, e.g. from a crashed go test, must not pass the gate.
+	if matched == 0 {
+		fmt.Fprintln(os.Stderr, "Error: no benchmark of the current run matches the baseline")
+		return 2
+	}
+
+	violations, err := cfg.Check(comparisons)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	unchecked, need := 0, 0
+	for _, v := range violations {
+		fmt.Println(v)
+		if v.NeedSamples > 0 {
+			unchecked, need = unchecked+1, v.NeedSamples
+		}
+	}
+	if unchecked > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: %d regression(s) could not be tested for significance, run with at least -count=%d\n", unchecked, need)
+	}
+	if len(violations) > 0 {
+		fmt.Fprintf(os.Stderr, "%d regression(s) beyond threshold\n", len(violations))
+		return 1
+	}
+	return 0
+}
+
+// flagSet reports whether the flag with the given name was set on the command line.
+func flagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/rah-0/testmark/benchutil"
+)
+
+// runCompare implements "testmark compare old.txt new.txt": it matches the benchmarks of two
+// captured runs by full name and prints old and new values with their percentage deltas.
+func runCompare(args []string) int {
+	fs := flag.NewFlagSet("testmark compare", flag.ExitOnError)
+	test := fs.String("test", "utest", "significance test for repeated runs: utest (Mann-Whitney U) or ttest (Welch's t-test)")
+	alpha := fs.Float64("alpha", 0.05, "significance level; deltas with a higher p-value are shown as ~")
+	strictEnv := fs.Bool("strict-env", false, "refuse to compare runs from different goos, goarch or cpu instead of warning")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: testmark compare old.txt new.txt")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return 2
+	}
+	ht, err := hypothesisTest(*test)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	old, oldEnv, err := readBenchLines(fs.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+	new, newEnv, err := readBenchLines(fs.Arg(1))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+	if !checkEnv(oldEnv, newEnv, *strictEnv) {
+		return 2
+	}
+
+	if err := writeComparisons(os.Stdout, benchutil.Compare(old, new), ht, *alpha); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// hypothesisTest returns the significance test for the given name.
+func hypothesisTest(name string) (benchutil.HypothesisTest, error) {
+	switch name {
+	case "utest":
+		return benchutil.MannWhitneyU, nil
+	case "ttest":
+		return benchutil.WelchTTest, nil
+	default:
+		return nil, fmt.Errorf("unknown test %q", name)
+	}
+}
+
+// readBenchLines reads a captured benchmark run and returns its benchmark lines
+// and the environment header of its first benchmark.
+func readBenchLines(path string) ([]benchutil.BenchLine, *benchutil.Env, error) {
+	records, err := readRecords(benchutil.NewFormatter(), []string{path})
+	if err != nil {
+		return nil, nil, err
+	}
+	return benchLines(records), benchutil.FirstEnv(records), nil
+}
+
+// checkEnv reports whether two runs may be compared. Runs from different environments
+// are refused if strict is set, and only produce a warning otherwise.
+func checkEnv(old, new *benchutil.Env, strict bool) bool {
+	if old == nil || new == nil {
+		return true
+	}
+	err := old.Compatible(*new)
+	if err == nil {
+		return true
+	}
+	if strict {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return false
+	}
+	fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	return true
+}
+
+// compareColumns are the metrics shown by the compare command with their human formatters.
+var compareColumns = []struct {
+	unit  string
+	title string
+	human func(float64) string
+}{
+	{"ns/op", "time/op", benchutil.HumanNsFloat},
+	{"B/op", "mem/op", func(v float64) string { return benchutil.HumanValue("B/op", v) }},
+	{"allocs/op", "allocs/op", func(v float64) string { return benchutil.HumanValue("allocs/op", v) }},
+}
+
+// writeComparisons prints comparisons as an aligned table with an old, new and delta column per metric.
+// Old and new values are means over repeated runs, and deltas of repeated runs are checked for significance.
+// Benchmarks that only appear in one run are marked as such.
+func writeComparisons(out io.Writer, comparisons []benchutil.Comparison, test benchutil.HypothesisTest, alpha float64) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprint(tw, "name")
+	for _, c := range compareColumns {
+		fmt.Fprintf(tw, "\told %s\tnew %s\tdelta", c.title, c.title)
+	}
+	fmt.Fprintln(tw)
+
+	for _, cmp := range comparisons {
+		fmt.Fprint(tw, cmp.Name)
+		switch {
+		case cmp.New == nil:
+			fmt.Fprint(tw, "\t(only in old)")
+		case cmp.Old == nil:
+			fmt.Fprint(tw, "\t(only in new)")
+		default:
+			for _, c := range compareColumns {
+				fmt.Fprintf(tw, "\t%s\t%s\t%s",
+					meanHuman(cmp.Old, c.unit, c.human),
+					meanHuman(cmp.New, c.unit, c.human),
+					cmp.FormatDelta(c.unit, test, alpha))
+			}
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+// meanHuman formats the mean of the metric with the given unit, or returns "-" if no run has it.
+func meanHuman(set *benchutil.SampleSet, unit string, human func(float64) string) string {
+	if s, ok := set.Summary(unit); ok {
+		return human(s.Mean)
+	}
+	return "-"
+}
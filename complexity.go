@@ -0,0 +1,105 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/rah-0/testmark/benchutil"
+)
+
+// runComplexity implements "testmark complexity": it reads benchmark output from the given files or stdin,
+// fits complexity models to every parameter sweep and prints the best fit.
+// With -max, it exits with status 1 if any sweep grows faster than the given model.
+func runComplexity(args []string) int {
+	fs := flag.NewFlagSet("testmark complexity", flag.ExitOnError)
+	dim := fs.String("dim", "", "name dimension swept by the benchmarks, e.g. size (default: the last name segment)")
+	metrics := fs.String("metric", "ns/op,B/op", "comma-separated metric units to analyze")
+	all := fs.Bool("all", false, "print the fit of every model instead of only the best one")
+	maxFlag := fs.String("max", "", "exit with status 1 if a best fit grows faster than this model, e.g. \"O(n log n)\"")
+	_ = fs.Parse(args)
+
+	limit := benchutil.Complexity(-1)
+	if *maxFlag != "" {
+		c, err := benchutil.ParseComplexity(*maxFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+		limit = c
+	}
+
+	records, err := readRecords(benchutil.NewFormatter(), fs.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+	lines := benchLines(records)
+
+	var analyses []benchutil.ComplexityAnalysis
+	for _, unit := range strings.Split(*metrics, ",") {
+		analyses = append(analyses, benchutil.AnalyzeComplexity(lines, *dim, strings.TrimSpace(unit))...)
+	}
+	if len(analyses) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: no parameter sweep with at least 3 numeric values found")
+		return 1
+	}
+
+	if err := writeComplexity(os.Stdout, analyses, *all); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		return 1
+	}
+
+	if limit >= 0 {
+		exceeded := false
+		for _, a := range analyses {
+			if a.Result.Best.Complexity > limit {
+				fmt.Fprintf(os.Stderr, "%s: %s is %s, above %s\n", a.Name, a.Unit, a.Result.Best.Complexity, limit)
+				exceeded = true
+			}
+		}
+		if exceeded {
+			return 1
+		}
+	}
+	return 0
+}
+
+// writeComplexity prints one row per sweep and metric with the best fit, its R², its coefficient
+// and the cost per element. If all is true, every model gets its own row.
+func writeComplexity(out io.Writer, analyses []benchutil.ComplexityAnalysis, all bool) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "benchmark\tunit\tpoints\tfit\tR²\tcoef\tper n")
+
+	for _, a := range analyses {
+		fits := []benchutil.ComplexityFit{a.Result.Best}
+		if all {
+			fits = a.Result.Fits
+		}
+		for _, fit := range fits {
+			model := fit.Complexity.String()
+			if all && fit.Complexity == a.Result.Best.Complexity {
+				model += " *"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%.4f\t%s\t%s\n",
+				a.Name, a.Unit, len(a.Points), model, fit.R2,
+				coefString(a.Unit, fit.Coef), coefString(a.Unit, a.Result.PerElement))
+		}
+	}
+	return tw.Flush()
+}
+
+// coefString formats a fitted coefficient of the given metric unit.
+// Time coefficients keep picoseconds in the human-readable form, e.g. "250ps".
+// Other coefficients below 1 would round down and are printed as plain numbers.
+func coefString(unit string, v float64) string {
+	if math.Abs(v) >= 1 || benchutil.IsTimeUnit(unit) {
+		return benchutil.HumanValue(unit, v)
+	}
+	return strconv.FormatFloat(v, 'g', 3, 64) + " " + unit
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rah-0/testmark/benchutil"
+)
+
+// runRecord implements "testmark record": it parses benchmark output from the given files or stdin
+// and appends it to the local history store together with the current git commit and branch.
+// Outside of a git repository the commit must be given with -commit.
+func runRecord(args []string) int {
+	fs := flag.NewFlagSet("testmark record", flag.ExitOnError)
+	dir := fs.String("dir", ".testmark", "directory of the history store")
+	commit := fs.String("commit", "", "commit hash to record (default: git rev-parse HEAD)")
+	branch := fs.String("branch", "", "branch to record (default: the current git branch)")
+	_ = fs.Parse(args)
+
+	// The store is keyed by commit, so an entry without one is refused rather than recorded.
+	if *commit == "" {
+		*commit = gitOutput("rev-parse", "HEAD")
+	}
+	if *commit == "" {
+		fmt.Fprintln(os.Stderr, "Error: cannot determine the git commit, pass it with -commit")
+		fs.Usage()
+		return 2
+	}
+	if *branch == "" {
+		*branch = gitOutput("rev-parse", "--abbrev-ref", "HEAD")
+	}
+
+	records, err := readRecords(benchutil.NewFormatter(), fs.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+	lines := benchLines(records)
+	if len(lines) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: no benchmark lines in input")
+		return 1
+	}
+
+	entry := benchutil.HistoryEntry{
+		Commit:     *commit,
+		Branch:     *branch,
+		Time:       time.Now().UTC(),
+		Env:        benchutil.FirstEnv(records),
+		Benchmarks: lines,
+	}
+	if err := benchutil.NewHistoryStore(*dir).Append(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing history: %v\n", err)
+		return 1
+	}
+	fmt.Fprintf(os.Stderr, "Recorded %d benchmark lines at %s\n", len(lines), shortCommit(*commit))
+	return 0
+}
+
+// runHistory implements "testmark history <bench>": it lists the recorded values of one benchmark, oldest first.
+func runHistory(args []string) int {
+	fs := flag.NewFlagSet("testmark history", flag.ExitOnError)
+	dir := fs.String("dir", ".testmark", "directory of the history store")
+	chart := fs.Bool("chart", false, "print a sparkline of every metric across the recorded runs")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: testmark history [flags] <benchmark>")
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	entries, err := benchutil.NewHistoryStore(*dir).Entries()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading history: %v\n", err)
+		return 1
+	}
+	points := benchutil.BenchHistory(entries, fs.Arg(0))
+	if len(points) == 0 {
+		fmt.Fprintf(os.Stderr, "No recorded runs of %s\n", fs.Arg(0))
+		return 1
+	}
+
+	// Runs with several GOMAXPROCS values get one row per value and a procs column.
+	byProcs := historyByProcs(points)
+	procsCol := ""
+	if len(byProcs) > 1 {
+		procsCol = "procs\t"
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "time\tcommit\tbranch\t"+procsCol+"n\ttime/op\tmem/op\tallocs/op")
+	for _, p := range points {
+		procs := ""
+		if procsCol != "" {
+			procs = strconv.Itoa(p.Procs) + "\t"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s%d\t%s\t%s\t%s\n",
+			p.Time.Local().Format("2006-01-02 15:04"),
+			shortCommit(p.Commit),
+			p.Branch,
+			procs,
+			len(p.Samples.Lines),
+			meanHuman(&p.Samples, "ns/op", benchutil.HumanNsFloat),
+			meanHuman(&p.Samples, "B/op", func(v float64) string { return benchutil.HumanValue("B/op", v) }),
+			meanHuman(&p.Samples, "allocs/op", func(v float64) string { return benchutil.HumanValue("allocs/op", v) }))
+	}
+	if err := tw.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		return 1
+	}
+
+	if *chart {
+		if err := writeSparklines(os.Stdout, byProcs); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// historyByProcs splits history points by their GOMAXPROCS value, in order of first appearance.
+func historyByProcs(points []benchutil.HistoryPoint) [][]benchutil.HistoryPoint {
+	var out [][]benchutil.HistoryPoint
+	index := map[int]int{}
+	for _, p := range points {
+		i, ok := index[p.Procs]
+		if !ok {
+			i = len(out)
+			index[p.Procs] = i
+			out = append(out, nil)
+		}
+		out[i] = append(out[i], p)
+	}
+	return out
+}
+
+// writeSparklines prints one sparkline per metric of the history points, oldest run first,
+// followed by the first and last mean. Runs without the metric leave a gap.
+// Every GOMAXPROCS value of byProcs gets its own sparklines, labeled with its procs if there are several.
+func writeSparklines(out io.Writer, byProcs [][]benchutil.HistoryPoint) error {
+	fmt.Fprintln(out)
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, points := range byProcs {
+		var units []string
+		seen := map[string]bool{}
+		for _, p := range points {
+			for _, u := range p.Samples.Units() {
+				if !seen[u] {
+					seen[u] = true
+					units = append(units, u)
+				}
+			}
+		}
+
+		for _, u := range units {
+			var means []float64
+			for _, p := range points {
+				mean := math.NaN()
+				if s, ok := p.Samples.Summary(u); ok {
+					mean = s.Mean
+				}
+				means = append(means, mean)
+			}
+			label := u
+			if len(byProcs) > 1 {
+				label = fmt.Sprintf("procs=%d %s", points[0].Procs, u)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s → %s\n", label, benchutil.Sparkline(means),
+				sparkValue(u, means[0]), sparkValue(u, means[len(means)-1]))
+		}
+	}
+	return tw.Flush()
+}
+
+// sparkValue formats a sparkline end point, "-" if the run lacks the metric.
+func sparkValue(unit string, v float64) string {
+	if math.IsNaN(v) {
+		return "-"
+	}
+	return benchutil.HumanValue(unit, v)
+}
+
+// gitOutput runs git with the given arguments and returns its trimmed output,
+// or an empty string if git fails, e.g. outside of a repository.
+func gitOutput(args ...string) string {
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// shortCommit abbreviates a commit hash to 7 characters.
+func shortCommit(c string) string {
+	if len(c) > 7 {
+		return c[:7]
+	}
+	return c
+}
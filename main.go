@@ -2,23 +2,300 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 
 	"github.com/rah-0/testmark/benchutil"
 )
 
-// main reads benchmark output line by line from stdin,
-// converts each line to a more readable format using benchutil,
-// and prints the result to stdout.
+// main dispatches to a subcommand when the first argument names one.
+// Without a subcommand, testmark reads benchmark output from stdin,
+// parses each benchmark line with benchutil and prints it in the selected output format.
 func main() {
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		line := scanner.Text()
-		fmt.Println(benchutil.AppendConvertedLine(line))
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "report":
+			os.Exit(runReport(os.Args[2:]))
+		case "compare":
+			os.Exit(runCompare(os.Args[2:]))
+		case "check":
+			os.Exit(runCheck(os.Args[2:]))
+		case "record":
+			os.Exit(runRecord(os.Args[2:]))
+		case "history":
+			os.Exit(runHistory(os.Args[2:]))
+		case "pivot":
+			os.Exit(runPivot(os.Args[2:]))
+		case "complexity":
+			os.Exit(runComplexity(os.Args[2:]))
+		case "scaling":
+			os.Exit(runScaling(os.Args[2:]))
+		case "chart":
+			os.Exit(runChart(os.Args[2:]))
+		}
+	}
+	os.Exit(runFormat(os.Args[1:]))
+}
+
+// chartWidth is the width in cells of the longest bar drawn by -chart.
+const chartWidth = 20
+
+// runFormat converts benchmark output from stdin line by line.
+// In text format, lines that are not benchmark lines are printed unchanged.
+func runFormat(args []string) int {
+	fs := flag.NewFlagSet("testmark", flag.ExitOnError)
+	format := fs.String("format", "text", "output format: text, json (one object per line), json-doc (single array), csv, tsv or markdown")
+	group := fs.Bool("group", false, "group benchmarks by sub-benchmark prefix: one table each in markdown, a group line or column otherwise")
+	groupBy := fs.String("group-by", "", "group benchmarks by the value of this name dimension, e.g. codec, like -group")
+	align := fs.Bool("align", false, "text: align the columns of every block of benchmark lines")
+	chart := fs.Bool("chart", false, "text: append a bar per benchmark, scaled within its sub-benchmark group")
+	chartMetric := fs.String("chart-metric", "ns/op", "metric unit drawn by -chart")
+	filter := fs.String("filter", "", "only keep benchmarks whose name dimensions match, e.g. size=1024,codec=zstd")
+	opsPerSec := fs.Bool("ops", false, "append the derived operations per second (OPS[...])")
+	allocRate := fs.Bool("alloc-rate", false, "append the derived bytes allocated per second (ALLOC[...])")
+	aggregate := fs.Bool("aggregate", false, "group repeated runs (-count=N) by name and print summary statistics")
+	noiseCV := fs.Float64("noise", 5, "aggregate: mark metrics whose coefficient of variation exceeds this percentage")
+	style := fs.String("style", "breakdown", "human units: breakdown (3ms 651µs 349ns) or compact (3.65ms)")
+	si := fs.Bool("si", false, "write byte sizes with decimal prefixes (kB, MB, GB) instead of KiB, MiB, GiB")
+	ascii := fs.Bool("ascii", false, "write us instead of µs")
+	input := fs.String("input", "auto", "input format: text, json (go test -json events) or auto to detect it")
+	_ = fs.Parse(args)
+
+	if *style != "breakdown" && *style != "compact" {
+		fmt.Fprintf(os.Stderr, "Error: unknown style %q\n", *style)
+		return 2
+	}
+	f := benchutil.NewFormatter().
+		SetOpsPerSec(*opsPerSec).
+		SetAllocRate(*allocRate).
+		SetStyle(benchutil.Style{Compact: *style == "compact", SI: *si, ASCII: *ascii})
+
+	if *input != "auto" && *input != "text" && *input != "json" {
+		fmt.Fprintf(os.Stderr, "Error: unknown input format %q\n", *input)
+		return 2
+	}
+	var dims map[string]string
+	if *filter != "" {
+		var err error
+		if dims, err = benchutil.ParseDimensionFilter(*filter); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+	}
+	if *aggregate {
+		if *format != "text" || *chart || *align || *group || *groupBy != "" {
+			fmt.Fprintln(os.Stderr, "Error: -aggregate only prints a text table and cannot be combined with -format, -chart, -align, -group or -group-by")
+			return 2
+		}
+		return runAggregate(f, *input, dims, *noiseCV)
+	}
+
+	var groupFn func(benchutil.Record) string
+	switch {
+	case *groupBy != "":
+		groupFn = benchutil.GroupByDimension(*groupBy)
+	case *group:
+		groupFn = benchutil.GroupBySubBenchmark
+	}
+	w, err := newRecordWriter(*format, os.Stdout, f, groupFn)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 2
+	}
+	if (*chart || *align) && *format != "text" {
+		fmt.Fprintln(os.Stderr, "Error: -chart and -align require the text format")
+		return 2
 	}
-	if err := scanner.Err(); err != nil {
+	switch {
+	case *chart:
+		w = benchutil.NewChartWriter(os.Stdout, f, *chartMetric, chartWidth, *align)
+	case *align:
+		w = benchutil.NewAlignedTextWriter(os.Stdout, f)
+	}
+	// The markdown writer splits its tables by group itself.
+	if groupFn != nil && *format != "markdown" && *format != "md" {
+		w = benchutil.NewGroupWriter(w, groupFn)
+	}
+
+	var writeErr error
+	err = streamRecords(f, os.Stdin, *input, func(r benchutil.Record) error {
+		if !matchRecord(r, dims) {
+			return nil
+		}
+		writeErr = w.Write(r)
+		return writeErr
+	})
+	if writeErr != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", writeErr)
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+	if err := w.Flush(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// runAggregate reads benchmark output from stdin and prints summary statistics of repeated runs.
+// Only benchmarks matching the dimension filter dims are aggregated.
+func runAggregate(f *benchutil.Formatter, input string, dims map[string]string, noiseCV float64) int {
+	records, err := scanRecords(f, os.Stdin, input)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
-		os.Exit(1)
+		return 1
+	}
+	records = filterRecords(records, dims)
+	if err := writeAggregates(os.Stdout, benchutil.GroupSamples(benchLines(records)), f.Style(), noiseCV); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// newRecordWriter returns the RecordWriter for the given output format name.
+// groupBy is only used by the markdown format, nil writes a single table.
+func newRecordWriter(format string, out io.Writer, f *benchutil.Formatter, groupBy func(benchutil.Record) string) (benchutil.RecordWriter, error) {
+	switch format {
+	case "text":
+		return benchutil.NewTextWriter(out, f), nil
+	case "json":
+		return benchutil.NewJSONWriter(out, false), nil
+	case "json-doc":
+		return benchutil.NewJSONWriter(out, true), nil
+	case "csv":
+		return benchutil.NewCSVWriter(out, ','), nil
+	case "tsv":
+		return benchutil.NewCSVWriter(out, '\t'), nil
+	case "markdown", "md":
+		return benchutil.NewMarkdownWriter(out, groupBy), nil
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// readRecords reads benchmark output from the given files, or from stdin if no file is given,
+// and converts every line into a Record. Text and go test -json input are detected automatically.
+func readRecords(f *benchutil.Formatter, paths []string) ([]benchutil.Record, error) {
+	if len(paths) == 0 {
+		return scanRecords(f, os.Stdin, "auto")
+	}
+
+	var out []benchutil.Record
+	for _, p := range paths {
+		file, err := os.Open(p)
+		if err != nil {
+			return nil, err
+		}
+		records, err := scanRecords(f, file, "auto")
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		out = append(out, records...)
+	}
+	return out, nil
+}
+
+// scanRecords converts every line read from r into a Record.
+func scanRecords(f *benchutil.Formatter, r io.Reader, input string) ([]benchutil.Record, error) {
+	var out []benchutil.Record
+	err := streamRecords(f, r, input, func(r benchutil.Record) error {
+		out = append(out, r)
+		return nil
+	})
+	return out, err
+}
+
+// streamRecords converts every line read from r into a Record and calls fn with it.
+// Each package of a go test -json stream gets its own benchutil.Stream, so interleaved
+// packages keep their own environment header.
+func streamRecords(f *benchutil.Formatter, r io.Reader, input string, fn func(benchutil.Record) error) error {
+	streams := map[string]*benchutil.Stream{}
+	return scanLines(r, input, func(pkg, line string) error {
+		s, ok := streams[pkg]
+		if !ok {
+			s = f.NewStream()
+			streams[pkg] = s
+		}
+		return fn(s.Record(line))
+	})
+}
+
+// scanLines calls fn for every line of benchmark output read from r.
+// If input is "json", r is decoded as a go test -json event stream and the output lines are rebuilt per package.
+// If input is "auto", the stream is decoded as JSON when it starts with a JSON object and read as text otherwise.
+// The package is only known for JSON input and is empty otherwise.
+func scanLines(r io.Reader, input string, fn func(pkg, line string) error) error {
+	br := bufio.NewReader(r)
+	if input == "auto" {
+		input = detectInput(br)
+	}
+
+	if input == "json" {
+		return benchutil.ScanTestJSON(br, fn)
+	}
+
+	scanner := bufio.NewScanner(br)
+	for scanner.Scan() {
+		if err := fn("", scanner.Text()); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// detectInput returns "json" if the first non-whitespace byte buffered from br opens a JSON object and "text" otherwise.
+// It only waits for as many bytes as it needs, so streamed input is not held back until a buffer fills up.
+func detectInput(br *bufio.Reader) string {
+	for n := 1; ; n++ {
+		prefix, err := br.Peek(n)
+		if err != nil {
+			return "text"
+		}
+		switch prefix[n-1] {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		if benchutil.IsTestJSON(prefix) {
+			return "json"
+		}
+		return "text"
+	}
+}
+
+// benchLines returns the parsed benchmark lines of the benchmark records.
+func benchLines(records []benchutil.Record) []benchutil.BenchLine {
+	var out []benchutil.BenchLine
+	for _, r := range records {
+		if r.Type == benchutil.RecordBenchmark {
+			out = append(out, *r.BenchLine)
+		}
+	}
+	return out
+}
+
+// matchRecord reports whether a record passes the dimension filter dims.
+// Log records and every record of an empty filter pass.
+func matchRecord(r benchutil.Record, dims map[string]string) bool {
+	return r.Type != benchutil.RecordBenchmark || len(dims) == 0 || r.MatchDimensions(dims)
+}
+
+// filterRecords returns the records that pass the dimension filter dims.
+func filterRecords(records []benchutil.Record, dims map[string]string) []benchutil.Record {
+	if len(dims) == 0 {
+		return records
+	}
+	var out []benchutil.Record
+	for _, r := range records {
+		if matchRecord(r, dims) {
+			out = append(out, r)
+		}
 	}
+	return out
 }
@@ -0,0 +1,46 @@
+package model
+
+// Duration is a length of time in nanoseconds that reads and writes human-readable strings
+// such as "3ms 651µs 349ns", the format of benchutil.HumanNs.
+// It implements encoding.TextMarshaler and encoding.TextUnmarshaler, so it is written as a string in JSON,
+// and it also accepts a plain number of nanoseconds when decoded from JSON.
+type Duration int64
+
+// ParseDuration parses a human-readable duration such as "3ms 651µs 349ns", "1.5s" or "-250ns".
+// Terms may use d, h, m, s, ms, µs, us, ns and ps, in any order and with or without spaces in between.
+// The total is rounded to the nearest nanosecond. Every string written by String parses back to the same value.
+func ParseDuration(s string) (Duration, error) {
+	v, err := parse(s, durationSymbols)
+	return Duration(v), err
+}
+
+// String returns the duration broken down into days, hours, minutes, seconds, milliseconds, microseconds and nanoseconds,
+// e.g. "2d 1h 1m 1s" or "-3ms 651µs 349ns".
+func (d Duration) String() string {
+	return Breakdown(int64(d), DurationUnits)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(b []byte) error {
+	v, err := ParseDuration(string(b))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// UnmarshalJSON accepts a string like "1ms 500µs" or a number of nanoseconds.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalJSON(b, durationSymbols)
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
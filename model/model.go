@@ -4,6 +4,12 @@ package model
 
 // Memory size constants in bytes using binary prefixes (powers of 1024)
 const (
+	// EiB represents one exbibyte (2^60 bytes)
+	EiB = int64(1 << 60)
+	// PiB represents one pebibyte (2^50 bytes)
+	PiB = int64(1 << 50)
+	// TiB represents one tebibyte (2^40 bytes)
+	TiB = int64(1 << 40)
 	// GiB represents one gibibyte (2^30 bytes)
 	GiB = int64(1 << 30)
 	// MiB represents one mebibyte (2^20 bytes)
@@ -12,8 +18,26 @@ const (
 	KiB = int64(1 << 10)
 )
 
+// Memory size constants in bytes using decimal SI prefixes (powers of 1000)
+const (
+	// EB represents one exabyte (10^18 bytes)
+	EB = int64(1000000000000000000)
+	// PB represents one petabyte (10^15 bytes)
+	PB = int64(1000000000000000)
+	// TB represents one terabyte (10^12 bytes)
+	TB = int64(1000000000000)
+	// GB represents one gigabyte (10^9 bytes)
+	GB = int64(1000000000)
+	// MB represents one megabyte (10^6 bytes)
+	MB = int64(1000000)
+	// KB represents one kilobyte (10^3 bytes)
+	KB = int64(1000)
+)
+
 // Time unit constants in nanoseconds
 const (
+	// Day represents one day in nanoseconds (8.64e13)
+	Day   = int64(86400000000000)
 	// Hour represents one hour in nanoseconds (3.6e12)
 	Hour  = int64(3600000000000)
 	// Min represents one minute in nanoseconds (6e10)
@@ -0,0 +1,45 @@
+package model
+
+// Size is a number of bytes that reads and writes human-readable strings such as "1KiB 104B",
+// the format of benchutil.HumanBytes.
+// It implements encoding.TextMarshaler and encoding.TextUnmarshaler, so it is written as a string in JSON,
+// and it also accepts a plain number of bytes when decoded from JSON.
+type Size int64
+
+// ParseSize parses a human-readable size such as "1KiB 104B", "1.5MiB" or "2MB".
+// Terms may use B, KiB, MiB, GiB, TiB, PiB, EiB and the decimal kB (or KB), MB, GB, TB, PB and EB, in any order and with or without
+// spaces in between. The total is rounded to the nearest byte. Every string written by String parses back to the same value.
+func ParseSize(s string) (Size, error) {
+	v, err := parse(s, sizeSymbols)
+	return Size(v), err
+}
+
+// String returns the size broken down into binary units up to EiB, e.g. "1KiB 104B" or "5TiB".
+func (s Size) String() string {
+	return Breakdown(int64(s), SizeUnits)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s Size) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *Size) UnmarshalText(b []byte) error {
+	v, err := ParseSize(string(b))
+	if err != nil {
+		return err
+	}
+	*s = v
+	return nil
+}
+
+// UnmarshalJSON accepts a string like "1KiB 104B" or a number of bytes.
+func (s *Size) UnmarshalJSON(b []byte) error {
+	v, err := unmarshalJSON(b, sizeSymbols)
+	if err != nil {
+		return err
+	}
+	*s = Size(v)
+	return nil
+}
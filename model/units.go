@@ -0,0 +1,186 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Unit is a unit symbol and its size in the base unit, e.g. {"KiB", 1024}.
+type Unit struct {
+	// Name is the symbol written after the number, e.g. "KiB"
+	Name string
+	// Size is the number of base units in one unit, e.g. 1024
+	Size int64
+}
+
+// DurationUnits are the time units written by Duration.String, from the largest to the smallest.
+// They are shared with the human-readable formatters of benchutil and must not be modified.
+var DurationUnits = []Unit{{"d", Day}, {"h", Hour}, {"m", Min}, {"s", Sec}, {"ms", Milli}, {"µs", Micro}, {"ns", 1}}
+
+// SizeUnits are the binary byte units written by Size.String, from the largest to the smallest.
+// They are shared with the human-readable formatters of benchutil and must not be modified.
+var SizeUnits = []Unit{{"EiB", EiB}, {"PiB", PiB}, {"TiB", TiB}, {"GiB", GiB}, {"MiB", MiB}, {"KiB", KiB}, {"B", 1}}
+
+// durationSymbols maps every time unit accepted by ParseDuration to its size in nanoseconds.
+var durationSymbols = map[string]float64{
+	"d":  float64(Day),
+	"h":  float64(Hour),
+	"m":  float64(Min),
+	"s":  float64(Sec),
+	"ms": float64(Milli),
+	"µs": float64(Micro),
+	"us": float64(Micro),
+	"ns": 1,
+	"ps": 1e-3,
+}
+
+// sizeSymbols maps every byte unit accepted by ParseSize to its size in bytes.
+var sizeSymbols = map[string]float64{
+	"B":   1,
+	"KiB": float64(KiB),
+	"MiB": float64(MiB),
+	"GiB": float64(GiB),
+	"TiB": float64(TiB),
+	"PiB": float64(PiB),
+	"EiB": float64(EiB),
+	"kB":  float64(KB),
+	"KB":  float64(KB),
+	"MB":  float64(MB),
+	"GB":  float64(GB),
+	"TB":  float64(TB),
+	"PB":  float64(PB),
+	"EB":  float64(EB),
+}
+
+// errOverflow is returned when a parsed value does not fit in an int64.
+var errOverflow = errors.New("value out of range")
+
+// Breakdown writes v as a sum of every unit it spans, largest first, e.g. "1KiB 24B".
+// units must be ordered from the largest to the smallest, and the last unit is the base unit,
+// which is also used for zero. Negative values are written with a leading "-", including math.MinInt64.
+func Breakdown(v int64, units []Unit) string {
+	sign := ""
+	if v < 0 {
+		sign = "-"
+	}
+	m := magnitude(v)
+
+	var parts []string
+	for _, u := range units[:len(units)-1] {
+		if size := uint64(u.Size); m >= size {
+			parts = append(parts, strconv.FormatUint(m/size, 10)+u.Name)
+			m %= size
+		}
+	}
+	if m > 0 || len(parts) == 0 {
+		parts = append(parts, strconv.FormatUint(m, 10)+units[len(units)-1].Name)
+	}
+	return sign + strings.Join(parts, " ")
+}
+
+// magnitude returns the absolute value of v, which fits in a uint64 even for math.MinInt64.
+func magnitude(v int64) uint64 {
+	if v < 0 {
+		return uint64(-(v + 1)) + 1
+	}
+	return uint64(v)
+}
+
+// parse reads a sum of "<number><unit>" terms, optionally separated by spaces and preceded by a sign,
+// such as "3ms 651µs" or "1.5KiB". The total is rounded to the nearest whole base unit.
+// A bare "0" is accepted without a unit. Totals beyond the int64 range are rejected,
+// except for a negative total of exactly math.MinInt64.
+func parse(s string, symbols map[string]float64) (int64, error) {
+	in := strings.TrimSpace(s)
+	neg := false
+	if strings.HasPrefix(in, "-") {
+		neg, in = true, strings.TrimSpace(in[1:])
+	} else if strings.HasPrefix(in, "+") {
+		in = strings.TrimSpace(in[1:])
+	}
+	if in == "0" {
+		return 0, nil
+	}
+	if in == "" {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+
+	limit := uint64(math.MaxInt64)
+	if neg {
+		limit++
+	}
+
+	var whole uint64
+	var frac float64
+	for in != "" {
+		i := 0
+		for i < len(in) && (in[i] >= '0' && in[i] <= '9' || in[i] == '.') {
+			i++
+		}
+		k := i
+		for k < len(in) && in[k] == ' ' {
+			k++
+		}
+		j := k
+		for j < len(in) && in[j] != ' ' && (in[j] < '0' || in[j] > '9') {
+			j++
+		}
+		num, sym := in[:i], in[k:j]
+		size, ok := symbols[sym]
+		if num == "" || !ok {
+			return 0, fmt.Errorf("invalid value %q: unknown term %q", s, in[:j])
+		}
+
+		if n, err := strconv.ParseUint(num, 10, 64); err == nil && size >= 1 {
+			// whole terms are summed exactly
+			term := n * uint64(size)
+			if n != 0 && (term/n != uint64(size) || term > limit || whole > limit-term) {
+				return 0, fmt.Errorf("invalid value %q: %w", s, errOverflow)
+			}
+			whole += term
+		} else {
+			f, err := strconv.ParseFloat(num, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q: bad number %q", s, num)
+			}
+			frac += f * size
+		}
+		in = strings.TrimLeft(in[j:], " ")
+	}
+
+	if frac != 0 {
+		total := math.Round(float64(whole) + frac)
+		// float64(limit) rounds up to 2^63, which is only reachable as math.MinInt64
+		if total > float64(limit) || (!neg && total >= float64(limit)) {
+			return 0, fmt.Errorf("invalid value %q: %w", s, errOverflow)
+		}
+		whole = uint64(total)
+	}
+	switch {
+	case neg && whole == limit:
+		return math.MinInt64, nil
+	case neg:
+		return -int64(whole), nil
+	default:
+		return int64(whole), nil
+	}
+}
+
+// unmarshalJSON decodes a JSON number as a raw value or a JSON string with parse.
+func unmarshalJSON(b []byte, symbols map[string]float64) (int64, error) {
+	if len(b) > 0 && b[0] == '"' {
+		s, err := strconv.Unquote(string(b))
+		if err != nil {
+			return 0, err
+		}
+		return parse(s, symbols)
+	}
+	n, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %s, want an integer or a string", b)
+	}
+	return n, nil
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Duration
+	}{
+		{"0", 0},
+		{"0ns", 0},
+		{"3ms 651µs 349ns", 3651349},
+		{"3ms651us349ns", 3651349},
+		{"1h 1m 1s", 3661 * Duration(Sec)},
+		{"1.5s", 1500 * Duration(Milli)},
+		{"2 ms", 2 * Duration(Milli)},
+		{"-250ns", -250},
+		{"1ns 500ps", 2},
+		{"750ps", 1},
+		{"9223372036854775807ns", math.MaxInt64},
+		{"-9223372036854775808ns", math.MinInt64},
+		{"2d 1h", 49 * Duration(Hour)},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDuration(%q) = %d, %v, want %d", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "ms", "5", "3 parsecs", "1.2.3s", "9223372036854775808ns", "-9223372036854775809ns", "2562048h", "106752d", "1e300h"} {
+		if _, err := ParseDuration(in); err == nil {
+			t.Errorf("ParseDuration(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Size
+	}{
+		{"0B", 0},
+		{"1KiB 104B", 1128},
+		{"1.5MiB", 3 * Size(MiB) / 2},
+		{"1kB 48B", 1048},
+		{"2MB", 2 * Size(MB)},
+		{"1GiB 1B", Size(GiB) + 1},
+		{"5TiB", 5 * Size(TiB)},
+		{"1PiB 2TB", Size(PiB) + 2*Size(TB)},
+		{"-8EiB", math.MinInt64},
+	}
+	for _, tt := range tests {
+		got, err := ParseSize(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseSize(%q) = %d, %v, want %d", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"1KiB 2ns", "1kiB", "B", "8EiB", "7EiB 1024PiB"} {
+		if _, err := ParseSize(in); err == nil {
+			t.Errorf("ParseSize(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestBreakdown(t *testing.T) {
+	units := []Unit{{Name: "kB", Size: KB}, {Name: "B", Size: 1}}
+	tests := []struct {
+		v    int64
+		want string
+	}{
+		{0, "0B"},
+		{1500, "1kB 500B"},
+		{2000, "2kB"},
+		{-1500, "-1kB 500B"},
+		{math.MinInt64, "-9223372036854775kB 808B"},
+	}
+	for _, tt := range tests {
+		if got := Breakdown(tt.v, units); got != tt.want {
+			t.Errorf("Breakdown(%d) = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestDurationSizeRoundTrip(t *testing.T) {
+	for _, d := range []Duration{0, 1, 999, 1000, 1500, 3651349, 3661000000000, -1234567, 3 * Duration(Day), math.MaxInt64, math.MinInt64} {
+		got, err := ParseDuration(d.String())
+		if err != nil || got != d {
+			t.Errorf("ParseDuration(%q) = %d, %v, want %d", d.String(), got, err, d)
+		}
+	}
+	for _, s := range []Size{0, 512, 1024, 1128, 1<<30 + 1<<20 + 1, -2048, 5 * Size(TiB), math.MaxInt64, math.MinInt64} {
+		got, err := ParseSize(s.String())
+		if err != nil || got != s {
+			t.Errorf("ParseSize(%q) = %d, %v, want %d", s.String(), got, err, s)
+		}
+	}
+}
+
+func TestDurationSizeJSON(t *testing.T) {
+	type budget struct {
+		Time Duration `json:"time"`
+		Mem  Size     `json:"mem"`
+	}
+
+	b, err := json.Marshal(budget{Time: 3651349, Mem: 1128})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"time":"3ms 651µs 349ns","mem":"1KiB 104B"}`; string(b) != want {
+		t.Errorf("json.Marshal() = %s, want %s", b, want)
+	}
+
+	var got budget
+	if err := json.Unmarshal(b, &got); err != nil || got.Time != 3651349 || got.Mem != 1128 {
+		t.Errorf("json.Unmarshal(%s) = %+v, %v", b, got, err)
+	}
+	if err := json.Unmarshal([]byte(`{"time":1500,"mem":"2MB"}`), &got); err != nil || got.Time != 1500 || got.Mem != 2*Size(MB) {
+		t.Errorf("json.Unmarshal(number) = %+v, %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(`{"time":"soon"}`), &got); err == nil {
+		t.Error("json.Unmarshal(soon) succeeded, want error")
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/rah-0/testmark/benchutil"
+)
+
+// runPivot implements "testmark pivot": it reads benchmark output from the given files or stdin
+// and prints one metric as a table with the values of one name dimension as rows and another as columns.
+func runPivot(args []string) int {
+	fs := flag.NewFlagSet("testmark pivot", flag.ExitOnError)
+	rows := fs.String("rows", "", "name dimension whose values become rows, e.g. size (required)")
+	cols := fs.String("cols", "", "name dimension whose values become columns, e.g. codec (required)")
+	metric := fs.String("metric", "ns/op", "metric unit shown in the cells")
+	format := fs.String("format", "text", "output format: text, markdown, csv or tsv")
+	filter := fs.String("filter", "", "only use benchmarks whose name dimensions match, e.g. op=read")
+	raw := fs.Bool("raw", false, "print the mean values as plain numbers instead of human-readable strings")
+	_ = fs.Parse(args)
+
+	if *rows == "" || *cols == "" {
+		fmt.Fprintln(os.Stderr, "Error: -rows and -cols are required")
+		fs.Usage()
+		return 2
+	}
+	var dims map[string]string
+	if *filter != "" {
+		var err error
+		if dims, err = benchutil.ParseDimensionFilter(*filter); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return 2
+		}
+	}
+
+	records, err := readRecords(benchutil.NewFormatter(), fs.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+	p := benchutil.Pivot(benchLines(filterRecords(records, dims)), *rows, *cols, *metric)
+	if len(p.Rows) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no benchmark has the dimensions %q and %q and the metric %q\n", *rows, *cols, *metric)
+		return 1
+	}
+
+	switch *format {
+	case "text":
+		err = p.WriteText(os.Stdout, *raw)
+	case "markdown", "md":
+		err = p.WriteMarkdown(os.Stdout, *raw)
+	case "csv":
+		err = p.WriteCSV(os.Stdout, ',', *raw)
+	case "tsv":
+		err = p.WriteCSV(os.Stdout, '\t', *raw)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown format %q\n", *format)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		return 1
+	}
+	return 0
+}
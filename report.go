@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/rah-0/testmark/benchutil"
+)
+
+// runReport implements "testmark report": it reads benchmark output from the given files
+// or stdin and writes a self-contained HTML report.
+func runReport(args []string) int {
+	fs := flag.NewFlagSet("testmark report", flag.ExitOnError)
+	out := fs.String("o", "report.html", "path of the HTML file to write")
+	_ = fs.Parse(args)
+
+	records, err := readRecords(benchutil.NewFormatter(), fs.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+
+	file, err := os.Create(*out)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return 1
+	}
+	if err := benchutil.WriteHTMLReport(file, records); err != nil {
+		file.Close()
+		fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+		return 1
+	}
+	if err := file.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing report: %v\n", err)
+		return 1
+	}
+	return 0
+}
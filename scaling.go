@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"text/tabwriter"
+
+	"github.com/rah-0/testmark/benchutil"
+)
+
+// runScaling implements "testmark scaling": it reads the output of benchmarks run with several
+// -cpu values from the given files or stdin and prints how each benchmark scales with GOMAXPROCS.
+func runScaling(args []string) int {
+	fs := flag.NewFlagSet("testmark scaling", flag.ExitOnError)
+	_ = fs.Parse(args)
+
+	records, err := readRecords(benchutil.NewFormatter(), fs.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading input: %v\n", err)
+		return 1
+	}
+	analyses := benchutil.AnalyzeScaling(benchLines(records))
+	if len(analyses) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: no benchmark was run with more than one GOMAXPROCS value, use go test -cpu=1,2,4,8")
+		return 1
+	}
+	if err := writeScaling(os.Stdout, analyses); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing output: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// writeScaling prints the speedup and efficiency of every benchmark per procs value,
+// followed by the Amdahl fit of every benchmark.
+func writeScaling(out io.Writer, analyses []benchutil.ScalingAnalysis) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "benchmark\tprocs\tn\ttime/op\tspeedup\tefficiency")
+	for _, a := range analyses {
+		name := a.Name
+		for _, p := range a.Points {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%.2fx\t%.1f%%\n",
+				name, p.Procs, p.N, benchutil.HumanValue("ns/op", p.NsPerOp), p.Speedup, p.Efficiency*100)
+			name = ""
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out)
+	tw = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "benchmark\tserial fraction\tmax speedup\tR²")
+	for _, a := range analyses {
+		maxSpeedup := "∞"
+		if !math.IsInf(a.MaxSpeedup, 1) {
+			maxSpeedup = fmt.Sprintf("%.2fx", a.MaxSpeedup)
+		}
+		fmt.Fprintf(tw, "%s\t%.1f%%\t%s\t%.4f\n", a.Name, a.SerialFraction*100, maxSpeedup, a.R2)
+	}
+	return tw.Flush()
+}